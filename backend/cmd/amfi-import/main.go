@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/pricing"
+	"investment-tracker-backend/services"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// amfi-import revalues mutual fund holdings from an AMFI NAVAll.txt file:
+//
+//	go run ./cmd/amfi-import -file NAVAll.txt
+func main() {
+	path := flag.String("file", "NAVAll.txt", "path to the AMFI NAVAll.txt file")
+	flag.Parse()
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using default values")
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatal("Failed to open NAV file:", err)
+	}
+	defer file.Close()
+
+	navs, err := pricing.ParseAMFINAV(file)
+	if err != nil {
+		log.Fatal("Invalid NAV file:", err)
+	}
+
+	config.ConnectDatabase()
+	defer config.DisconnectDatabase()
+
+	summary, err := services.ImportAMFINAV(config.DB, navs)
+	if err != nil {
+		log.Fatal("Failed to import NAVs:", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(summary)
+
+	log.Printf("✅ Revalued %d of %d mutual fund holdings, %d unmatched schemes",
+		summary.HoldingsRevalued, summary.HoldingsChecked, len(summary.UnmatchedSchemes))
+}
+
+//
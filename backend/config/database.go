@@ -69,10 +69,44 @@ func ConnectDatabase() {
 		&models.Expense{},
 		&models.Goal{},
 		&models.Investment{},
+		&models.InvestmentTransaction{},
+		&models.PortfolioSnapshot{},
+		&models.AllocationTarget{},
+		&models.IncomeEvent{},
+		&models.CorporateAction{},
+		&models.CorporateActionAudit{},
+		&models.StatusRule{},
+		&models.Benchmark{},
+		&models.BenchmarkPrice{},
+		&models.FXRate{},
+		&models.SIP{},
+		&models.InvestmentGoalAllocation{},
+		&models.GoalProgressEvent{},
+		&models.PlannedContribution{},
+		&models.GoalContribution{},
+		&models.BudgetCategory{},
 	)
 	if err != nil {
 		log.Fatal("Failed to auto-migrate models:", err)
 	}
+
+	// Investments linked before goal allocations existed count fully towards their goal
+	if err := DB.Exec(`INSERT INTO investment_goal_allocations (created_at, updated_at, user_id, investment_id, goal_id, percent)
+		SELECT NOW(), NOW(), i.user_id, i.id, i.goal_id, 100 FROM investments i
+		JOIN goals g ON g.id = i.goal_id AND g.deleted_at IS NULL
+		WHERE i.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM investment_goal_allocations a WHERE a.investment_id = i.id)`).Error; err != nil {
+		log.Fatal("Failed to backfill goal allocations:", err)
+	}
+
+	// Amounts entered on goals before contributions existed become an opening deposit
+	if err := DB.Exec(`INSERT INTO goal_contributions (created_at, updated_at, user_id, goal_id, type, amount, currency, date, notes)
+		SELECT NOW(), NOW(), g.user_id, g.id, 'Deposit', g.current_amount, g.currency, g.created_at, 'Opening balance' FROM goals g
+		WHERE g.deleted_at IS NULL AND g.current_amount > 0
+		AND NOT EXISTS (SELECT 1 FROM investment_goal_allocations a WHERE a.goal_id = g.id)
+		AND NOT EXISTS (SELECT 1 FROM goal_contributions c WHERE c.goal_id = g.id)`).Error; err != nil {
+		log.Fatal("Failed to backfill goal contributions:", err)
+	}
 	log.Println("Auto-migration completed successfully")
 }
 
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"errors"
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/finance"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetBenchmarks lists the benchmarks available for comparison
+func GetBenchmarks(c *gin.Context) {
+	var benchmarks []models.Benchmark
+	if err := config.DB.Order("name ASC").Find(&benchmarks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"benchmarks": benchmarks,
+		"default":    os.Getenv("DEFAULT_BENCHMARK"),
+		"count":      len(benchmarks),
+	})
+}
+
+// ImportBenchmark loads a benchmark's daily closes from an uploaded "date,close" CSV.
+// The file can be sent as a multipart "file" field or as the raw request body.
+func ImportBenchmark(c *gin.Context) {
+	name := c.Query("name")
+	if name == "" {
+		name = c.PostForm("name")
+	}
+	if strings.TrimSpace(name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Benchmark name is required"})
+		return
+	}
+
+	var reader io.Reader = c.Request.Body
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	count, err := services.ImportBenchmarkCSV(config.DB, name, reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid benchmark file: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"benchmark": strings.ToUpper(strings.TrimSpace(name)),
+		"prices":    count,
+	})
+}
+
+// GetBenchmarkComparison compares each investment, and the whole portfolio,
+// with the same cash flows invested in a benchmark
+func GetBenchmarkComparison(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	name := benchmarkName(c)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Benchmark is required"})
+		return
+	}
+
+	var investments []models.Investment
+	if err := config.DB.Where("user_id = ?", uint(userID)).Find(&investments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	holdings, portfolio, err := compareWithBenchmark(investments, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Benchmark not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"benchmark":   portfolio.Benchmark,
+		"portfolio":   portfolio,
+		"investments": holdings,
+	})
+}
+
+// benchmarkName returns the benchmark requested with ?benchmark=, or the
+// DEFAULT_BENCHMARK environment variable
+func benchmarkName(c *gin.Context) string {
+	if name := strings.TrimSpace(c.Query("benchmark")); name != "" {
+		return name
+	}
+	return strings.TrimSpace(os.Getenv("DEFAULT_BENCHMARK"))
+}
+
+// compareWithBenchmark compares each investment's cash flows, and their
+// combined flows, with the named benchmark
+func compareWithBenchmark(investments []models.Investment, name string) ([]services.BenchmarkComparison, services.BenchmarkComparison, error) {
+	benchmark, series, err := services.LoadBenchmarkSeries(config.DB, name)
+	if err != nil {
+		return nil, services.BenchmarkComparison{}, err
+	}
+
+	ledgers, err := loadLedgers(investments)
+	if err != nil {
+		return nil, services.BenchmarkComparison{}, err
+	}
+
+	now := time.Now()
+	holdings := make([]services.BenchmarkComparison, 0, len(investments))
+	var allFlows []finance.CashFlow
+	var totalValue models.Money
+	for i := range investments {
+		inv := &investments[i]
+		flows := inv.ContributionFlows(ledgers[inv.ID])
+
+		comparison := services.CompareWithBenchmark(flows, inv.CurrentValue, benchmark.Name, series, now)
+		comparison.InvestmentID = inv.ID
+		comparison.Name = inv.Name
+		holdings = append(holdings, comparison)
+
+		allFlows = append(allFlows, flows...)
+		totalValue += inv.CurrentValue
+	}
+
+	portfolio := services.CompareWithBenchmark(allFlows, totalValue, benchmark.Name, series, now)
+	return holdings, portfolio, nil
+}
+
+//
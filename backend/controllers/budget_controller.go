@@ -3,6 +3,7 @@ package controllers
 import (
 	"investment-tracker-backend/config"
 	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
 	"net/http"
 	"strconv"
 
@@ -61,6 +62,12 @@ func GetBudget(c *gin.Context) {
 		return
 	}
 
+	// Per-category limits with what was spent so far
+	if err := services.LoadBudgetBreakdown(config.DB, &budget); err != nil {
+		c.JSON(projectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, budget)
 }
 
@@ -86,6 +93,16 @@ func CreateBudget(c *gin.Context) {
 	}
 
 	budget.UserID = uint(userID)
+	if budget.Currency, err = resolveCurrency(budget.Currency, models.DefaultCurrency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	categories := budget.Categories
+	budget.Categories = nil
+	if err := validateBudgetCategories(budget, categories); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Calculate savings
 	budget.CalculateSavings()
@@ -95,6 +112,17 @@ func CreateBudget(c *gin.Context) {
 		return
 	}
 
+	if len(categories) > 0 {
+		if err := services.SaveBudgetCategories(config.DB, &budget, categories); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save budget categories: " + err.Error()})
+			return
+		}
+		if err := services.LoadBudgetBreakdown(config.DB, &budget); err != nil {
+			c.JSON(projectionErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	c.JSON(http.StatusCreated, budget)
 }
 
@@ -135,6 +163,28 @@ func UpdateBudget(c *gin.Context) {
 
 	budget.ID = uint(budgetID)
 	budget.UserID = uint(userID)
+	if budget.Currency, err = resolveCurrency(budget.Currency, existingBudget.Currency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Categories are only replaced when sent; an empty list removes them
+	categories := budget.Categories
+	budget.Categories = nil
+	if err := validateBudgetCategories(budget, categories); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Expenses already recorded count in the new currency
+	if budget.Currency != existingBudget.Currency {
+		fx := services.NewFXConverter(config.DB)
+		if budget.TotalExpenses, err = services.BudgetExpensesTotal(config.DB, fx, budget); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		budget.MissingFXRates = fx.Missing()
+	}
 
 	// Recalculate savings
 	budget.CalculateSavings()
@@ -144,6 +194,17 @@ func UpdateBudget(c *gin.Context) {
 		return
 	}
 
+	if categories != nil {
+		if err := services.SaveBudgetCategories(config.DB, &budget, categories); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save budget categories: " + err.Error()})
+			return
+		}
+	}
+	if err := services.LoadBudgetBreakdown(config.DB, &budget); err != nil {
+		c.JSON(projectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, budget)
 }
 
@@ -184,4 +245,16 @@ func DeleteBudget(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Budget deleted successfully"})
 }
 
+// validateBudgetCategories checks the category limits sent for a budget, which
+// need the budget's month to match expenses against
+func validateBudgetCategories(budget models.Budget, categories []models.BudgetCategory) error {
+	if len(categories) == 0 {
+		return nil
+	}
+	if _, _, err := budget.MonthRange(); err != nil {
+		return err
+	}
+	return models.ValidateBudgetCategories(categories)
+}
+
 //
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCorporateActions retrieves corporate actions, optionally filtered by ?symbol=
+func GetCorporateActions(c *gin.Context) {
+	query := config.DB.Order("ex_date DESC")
+	if symbol := c.Query("symbol"); symbol != "" {
+		query = query.Where("UPPER(symbol) = UPPER(?)", symbol)
+	}
+
+	var actions []models.CorporateAction
+	if err := query.Find(&actions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, actions)
+}
+
+// CreateCorporateAction records a split, bonus, merger or symbol change without applying it
+func CreateCorporateAction(c *gin.Context) {
+	var action models.CorporateAction
+	if err := c.ShouldBindJSON(&action); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+		return
+	}
+
+	action.ID = 0
+	action.AppliedAt = nil
+	action.ReversedAt = nil
+
+	if err := action.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := config.DB.Create(&action).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create corporate action: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, action)
+}
+
+// ApplyCorporateAction adjusts every holding of the action's symbol
+func ApplyCorporateAction(c *gin.Context) {
+	action, ok := findCorporateAction(c)
+	if !ok {
+		return
+	}
+
+	count, err := services.ApplyCorporateAction(config.DB, &action)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to apply corporate action: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Corporate action applied successfully",
+		"corporate_action":  action,
+		"holdings_adjusted": count,
+	})
+}
+
+// ReverseCorporateAction undoes an applied corporate action from its audit trail
+func ReverseCorporateAction(c *gin.Context) {
+	action, ok := findCorporateAction(c)
+	if !ok {
+		return
+	}
+
+	count, err := services.ReverseCorporateAction(config.DB, &action)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to reverse corporate action: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "Corporate action reversed successfully",
+		"corporate_action":  action,
+		"holdings_adjusted": count,
+	})
+}
+
+// GetCorporateActionAudit retrieves the audit trail of a corporate action
+func GetCorporateActionAudit(c *gin.Context) {
+	action, ok := findCorporateAction(c)
+	if !ok {
+		return
+	}
+
+	var audits []models.CorporateActionAudit
+	if err := config.DB.Where("corporate_action_id = ?", action.ID).Order("created_at ASC, id ASC").Find(&audits).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"corporate_action": action,
+		"audit":            audits,
+	})
+}
+
+// findCorporateAction loads the corporate action named by the :id parameter,
+// writing an error response when it cannot
+func findCorporateAction(c *gin.Context) (models.CorporateAction, bool) {
+	var action models.CorporateAction
+
+	actionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return action, false
+	}
+
+	if err := config.DB.First(&action, uint(actionID)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Corporate action not found"})
+		return action, false
+	}
+	return action, true
+}
+
+//
@@ -1,23 +1,33 @@
 package controllers
 
 import (
+	"errors"
 	"investment-tracker-backend/config"
 	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type DashboardResponse struct {
-	TotalInvestments float64             `json:"total_investments"`
-	TotalGains       float64             `json:"total_gains"`
-	MonthlyIncome    float64             `json:"monthly_income"`
-	MonthlyExpenses  float64             `json:"monthly_expenses"`
-	MonthlySavings   float64             `json:"monthly_savings"`
-	Investments      []models.Investment `json:"investments"`
-	Goals            []models.Goal       `json:"goals"`
-	RecentExpenses   []models.Expense    `json:"recent_expenses"`
+	BaseCurrency     string                        `json:"base_currency"` // Currency the totals are converted to
+	TotalInvestments models.Money                  `json:"total_investments"`
+	TotalGains       models.Money                  `json:"total_gains"`
+	PortfolioXIRR    *float64                      `json:"portfolio_xirr,omitempty"`
+	PortfolioCAGR    *float64                      `json:"portfolio_cagr,omitempty"`
+	PortfolioAlpha   *float64                      `json:"portfolio_alpha,omitempty"` // Portfolio XIRR minus benchmark XIRR
+	Benchmark        *services.BenchmarkComparison `json:"benchmark,omitempty"`
+	PassiveIncomeTTM models.Money                  `json:"passive_income_ttm"` // Income received over the trailing 12 months
+	MonthlyIncome    models.Money                  `json:"monthly_income"`
+	MonthlyExpenses  models.Money                  `json:"monthly_expenses"`
+	MonthlySavings   models.Money                  `json:"monthly_savings"`
+	Investments      []models.Investment           `json:"investments"`
+	Goals            []models.Goal                 `json:"goals"`
+	RecentExpenses   []models.Expense              `json:"recent_expenses"`
+	MissingFXRates   []string                      `json:"missing_fx_rates,omitempty"` // Currency pairs left out of the totals for lack of a rate
 }
 
 // GetDashboard retrieves dashboard summary data for the authenticated user
@@ -37,28 +47,82 @@ func GetDashboard(c *gin.Context) {
 
 	var response DashboardResponse
 
+	// Amounts are reported in the user's base currency
+	var user models.User
+	if err := config.DB.Select("id", "base_currency").First(&user, uint(userID)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	base := models.NormalizeCurrency(user.BaseCurrency)
+	response.BaseCurrency = base
+	fx := services.NewFXConverter(config.DB)
+	now := time.Now()
+
 	// Get all investments for this user only
 	var investments []models.Investment
 	if err := config.DB.Where("user_id = ?", uint(userID)).Find(&investments).Error; err == nil {
 		response.Investments = investments
 
-		// Calculate total investments and gains
-		var totalInvested float64
-		var totalCurrent float64
+		// Calculate total investments and gains: current values at today's
+		// rates, invested amounts at the rates of the dates they were paid.
+		// Holdings without a rate are left out and reported.
+		ledgers, err := loadLedgers(investments)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		var totalInvested models.Money
+		var totalCurrent models.Money
 		for _, inv := range investments {
-			totalInvested += inv.Invested
-			totalCurrent += inv.CurrentValue
+			current, err := fx.Convert(inv.CurrentValue, inv.Currency, base, now)
+			if errors.Is(err, services.ErrNoFXRate) {
+				continue
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			invested, err := fx.ConvertInvested(inv, ledgers[inv.ID], base)
+			if errors.Is(err, services.ErrNoFXRate) {
+				continue
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			totalInvested += invested
+			totalCurrent += current
 		}
 		response.TotalInvestments = totalCurrent
 		response.TotalGains = totalCurrent - totalInvested
+
+		// Money-weighted and compound annual returns across the whole portfolio
+		if flows, err := annualizeInvestments(investments, base); err == nil {
+			response.PortfolioXIRR, response.PortfolioCAGR = models.AnnualizedReturns(flows, time.Now())
+		}
+
+		// Compare with the requested or default benchmark when one is loaded
+		if name := benchmarkName(c); name != "" {
+			if _, portfolio, err := compareWithBenchmark(investments, name); err == nil {
+				response.Benchmark = &portfolio
+				response.PortfolioAlpha = portfolio.Alpha
+			}
+		}
+	}
+
+	// Dividends, interest and coupons received over the last 12 months
+	if income, err := services.PassiveIncome(config.DB, fx, uint(userID), now.AddDate(-1, 0, 0), now, base); err == nil {
+		response.PassiveIncomeTTM = income
 	}
 
 	// Get current month budget (most recent) for this user only
 	var budget models.Budget
 	if err := config.DB.Where("user_id = ?", uint(userID)).Order("created_at DESC").First(&budget).Error; err == nil {
-		response.MonthlyIncome = budget.Income
-		response.MonthlyExpenses = budget.TotalExpenses
-		response.MonthlySavings = budget.Savings
+		if rate, err := fx.Rate(budget.Currency, base, now); err == nil {
+			response.MonthlyIncome = budget.Income.Mul(rate)
+			response.MonthlyExpenses = budget.TotalExpenses.Mul(rate)
+			response.MonthlySavings = budget.Savings.Mul(rate)
+		}
 	}
 
 	// Get active goals (not completed, limit 5) for this user only
@@ -73,6 +137,8 @@ func GetDashboard(c *gin.Context) {
 		response.RecentExpenses = expenses
 	}
 
+	response.MissingFXRates = fx.Missing()
+
 	c.JSON(http.StatusOK, response)
 }
 
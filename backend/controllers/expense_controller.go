@@ -1,10 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"investment-tracker-backend/config"
 	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -86,6 +90,22 @@ func CreateExpense(c *gin.Context) {
 	}
 
 	expense.UserID = uint(userID)
+	if expense.Currency, err = resolveCurrency(expense.Currency, models.DefaultCurrency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Expenses count towards a budget in the budget's currency
+	var budget models.Budget
+	var budgetAmount models.Money
+	if expense.BudgetID != nil {
+		if err := config.DB.First(&budget, *expense.BudgetID).Error; err == nil {
+			if budgetAmount, err = expenseInBudgetCurrency(expense, budget); err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
 
 	if err := config.DB.Create(&expense).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -93,15 +113,15 @@ func CreateExpense(c *gin.Context) {
 	}
 
 	// Update budget total expenses if budget_id is provided
-	if expense.BudgetID != nil {
-		var budget models.Budget
-		if err := config.DB.First(&budget, *expense.BudgetID).Error; err == nil {
-			budget.TotalExpenses += expense.Amount
-			budget.CalculateSavings()
-			config.DB.Save(&budget)
-		}
+	if budget.ID != 0 {
+		budget.TotalExpenses += budgetAmount
+		budget.CalculateSavings()
+		config.DB.Save(&budget)
 	}
 
+	// Goals sized from spending follow the new average
+	refreshTemplateGoals(uint(userID), expense.Date)
+
 	c.JSON(http.StatusCreated, expense)
 }
 
@@ -142,25 +162,44 @@ func UpdateExpense(c *gin.Context) {
 
 	expense.ID = uint(expenseID)
 	expense.UserID = uint(userID)
-
-	if err := config.DB.Save(&expense).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if expense.Currency, err = resolveCurrency(expense.Currency, oldExpense.Currency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Update budget total expenses
+	// Expenses count towards a budget in the budget's currency
+	var budget models.Budget
+	var oldAmount, newAmount models.Money
 	if oldExpense.BudgetID != nil {
-		var budget models.Budget
 		if err := config.DB.First(&budget, *oldExpense.BudgetID).Error; err == nil {
-			budget.TotalExpenses -= oldExpense.Amount
+			if oldAmount, err = expenseInBudgetCurrency(oldExpense, budget); err != nil {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+				return
+			}
 			if expense.BudgetID != nil && *expense.BudgetID == *oldExpense.BudgetID {
-				budget.TotalExpenses += expense.Amount
+				if newAmount, err = expenseInBudgetCurrency(expense, budget); err != nil {
+					c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+					return
+				}
 			}
-			budget.CalculateSavings()
-			config.DB.Save(&budget)
 		}
 	}
 
+	if err := config.DB.Save(&expense).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Update budget total expenses
+	if budget.ID != 0 {
+		budget.TotalExpenses += newAmount - oldAmount
+		budget.CalculateSavings()
+		config.DB.Save(&budget)
+	}
+
+	// Goals sized from spending follow the new average
+	refreshTemplateGoals(uint(userID), oldExpense.Date, expense.Date)
+
 	c.JSON(http.StatusOK, expense)
 }
 
@@ -193,13 +232,20 @@ func DeleteExpense(c *gin.Context) {
 		return
 	}
 
-	// Update budget total expenses before deleting
+	// Work out what the expense counted for in its budget before deleting
+	fx := services.NewFXConverter(config.DB)
+	var budget models.Budget
+	var amount models.Money
+	rateMissing := false
 	if expense.BudgetID != nil {
-		var budget models.Budget
 		if err := config.DB.First(&budget, *expense.BudgetID).Error; err == nil {
-			budget.TotalExpenses -= expense.Amount
-			budget.CalculateSavings()
-			config.DB.Save(&budget)
+			amount, err = fx.Convert(expense.Amount, expense.Currency, budget.Currency, expense.Date)
+			if errors.Is(err, services.ErrNoFXRate) {
+				rateMissing = true
+			} else if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
 		}
 	}
 
@@ -208,7 +254,51 @@ func DeleteExpense(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Expense deleted successfully"})
+	// Update budget total expenses. Without a rate for the deleted expense,
+	// the total is added up again from the expenses that are left.
+	if budget.ID != 0 {
+		if rateMissing {
+			total, err := services.BudgetExpensesTotal(config.DB, fx, budget)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			budget.TotalExpenses = total
+		} else {
+			budget.TotalExpenses -= amount
+		}
+		budget.CalculateSavings()
+		config.DB.Save(&budget)
+	}
+
+	// Goals sized from spending follow the new average
+	refreshTemplateGoals(uint(userID), expense.Date)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Expense deleted successfully",
+		"missing_fx_rates": fx.Missing(),
+	})
+}
+
+// refreshTemplateGoals resizes the user's goals sized from spending when an
+// expense on one of the dates counts towards the average. Failures are logged,
+// as the expense itself has been saved.
+func refreshTemplateGoals(userID uint, dates ...time.Time) {
+	now := time.Now()
+	for _, date := range dates {
+		if services.InSpendingWindow(date, now) {
+			if err := services.RefreshTemplateGoals(config.DB, userID, now); err != nil {
+				log.Printf("❌ Failed to resize templated goals of user %d: %v", userID, err)
+			}
+			return
+		}
+	}
+}
+
+// expenseInBudgetCurrency converts an expense to its budget's currency at the
+// rate for the expense date
+func expenseInBudgetCurrency(expense models.Expense, budget models.Budget) (models.Money, error) {
+	return services.NewFXConverter(config.DB).Convert(expense.Amount, expense.Currency, budget.Currency, expense.Date)
 }
 
 //
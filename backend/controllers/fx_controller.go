@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"fmt"
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetFXRates lists exchange rates, optionally filtered by ?from= and ?to= currency
+func GetFXRates(c *gin.Context) {
+	query := config.DB.Model(&models.FXRate{})
+	if from := c.Query("from"); from != "" {
+		query = query.Where("from_currency = ?", models.NormalizeCurrency(from))
+	}
+	if to := c.Query("to"); to != "" {
+		query = query.Where("to_currency = ?", models.NormalizeCurrency(to))
+	}
+
+	var rates []models.FXRate
+	if err := query.Order("date DESC, from_currency ASC, to_currency ASC").Limit(1000).Find(&rates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rates": rates,
+		"count": len(rates),
+	})
+}
+
+// ConvertCurrency converts ?amount= from one currency to another at the rate for ?date= (default today)
+func ConvertCurrency(c *gin.Context) {
+	amount, err := strconv.ParseFloat(c.DefaultQuery("amount", "1"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid amount"})
+		return
+	}
+
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+			return
+		}
+		date = parsed
+	}
+
+	from, to := models.NormalizeCurrency(c.Query("from")), models.NormalizeCurrency(c.Query("to"))
+	rate, err := services.NewFXConverter(config.DB).Rate(from, to, date)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":      from,
+		"to":        to,
+		"date":      date.Format("2006-01-02"),
+		"rate":      rate,
+		"amount":    amount,
+		"converted": amount * rate,
+	})
+}
+
+// ImportFXRates loads exchange rates from an uploaded "date,from,to,rate" CSV.
+// The file can be sent as a multipart "file" field or as the raw request body.
+func ImportFXRates(c *gin.Context) {
+	var reader io.Reader = c.Request.Body
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	count, err := services.ImportFXRatesCSV(config.DB, reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid FX rate file: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rates": count})
+}
+
+// resolveCurrency normalizes a currency code sent in a request, falling back
+// to previous when none was sent
+func resolveCurrency(code, previous string) (string, error) {
+	if strings.TrimSpace(code) == "" {
+		return models.NormalizeCurrency(previous), nil
+	}
+	code = models.NormalizeCurrency(code)
+	if !models.ValidCurrency(code) {
+		return "", fmt.Errorf("Invalid currency code: %s", code)
+	}
+	return code, nil
+}
+
+//
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetGoalContributions retrieves the manual deposits and withdrawals of a goal
+func GetGoalContributions(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	goalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID format"})
+		return
+	}
+
+	// Verify the goal belongs to the user
+	var goal models.Goal
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(goalID), uint(userID)).First(&goal).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	var contributions []models.GoalContribution
+	if err := config.DB.Where("goal_id = ?", goal.ID).Order("date ASC, id ASC").Find(&contributions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	fx := services.NewFXConverter(config.DB)
+	total, err := services.GoalContributionsTotal(config.DB, fx, goal, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"goal_id":          goal.ID,
+		"contributions":    contributions,
+		"total":            total,
+		"currency":         models.NormalizeCurrency(goal.Currency),
+		"count":            len(contributions),
+		"missing_fx_rates": fx.Missing(),
+	})
+}
+
+// CreateGoalContribution records cash put towards a goal, or taken out of it
+func CreateGoalContribution(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	goalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID format"})
+		return
+	}
+
+	var goal models.Goal
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(goalID), uint(userID)).First(&goal).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	var contribution models.GoalContribution
+	if err := c.ShouldBindJSON(&contribution); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+		return
+	}
+
+	contribution.ID = 0
+	contribution.UserID = uint(userID)
+	contribution.GoalID = goal.ID
+	if contribution.Currency, err = resolveCurrency(contribution.Currency, goal.Currency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := contribution.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The amount has to be convertible to the goal's currency, and a goal cannot give out more than it holds
+	amount, err := services.NewFXConverter(config.DB).Convert(contribution.Amount, contribution.Currency, goal.Currency, contribution.Date)
+	if err != nil {
+		c.JSON(projectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if contribution.Type == models.GoalContributionWithdrawal && amount > goal.CurrentAmount {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Withdrawal is more than the goal's current amount"})
+		return
+	}
+
+	if err := config.DB.Create(&contribution).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create contribution: " + err.Error()})
+		return
+	}
+
+	if err := refreshContributionGoal(&goal); err != nil {
+		c.JSON(projectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"contribution": contribution,
+		"goal":         goal,
+	})
+}
+
+// DeleteGoalContribution removes a deposit or withdrawal from a goal
+func DeleteGoalContribution(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	goalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID format"})
+		return
+	}
+
+	contributionID, err := strconv.ParseUint(c.Param("contribution_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid contribution ID format"})
+		return
+	}
+
+	var goal models.Goal
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(goalID), uint(userID)).First(&goal).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	var contribution models.GoalContribution
+	if err := config.DB.Where("id = ? AND goal_id = ?", uint(contributionID), goal.ID).First(&contribution).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Contribution not found"})
+		return
+	}
+
+	if err := config.DB.Delete(&contribution).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := refreshContributionGoal(&goal); err != nil {
+		c.JSON(projectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Contribution deleted successfully",
+		"goal":    goal,
+	})
+}
+
+// refreshContributionGoal recalculates the goal's current_amount and reloads it
+func refreshContributionGoal(goal *models.Goal) error {
+	if err := updateGoalCurrentAmount(goal.ID); err != nil {
+		return err
+	}
+	if err := config.DB.First(goal, goal.ID).Error; err != nil {
+		return err
+	}
+	goal.CalculateInflation()
+	return nil
+}
+
+//
@@ -1,15 +1,25 @@
 package controllers
 
 import (
+	"errors"
 	"investment-tracker-backend/config"
 	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Number of Monte Carlo paths simulated for a goal by default and at most
+const (
+	defaultSimulationPaths = 1000
+	maxSimulationPaths     = 10000
+)
+
 // GetGoals retrieves all goals for the authenticated user
 func GetGoals(c *gin.Context) {
 	// Get user_id from context
@@ -31,9 +41,36 @@ func GetGoals(c *gin.Context) {
 		return
 	}
 
+	// Inflated target, required contribution, projected completion and pace for each goal
+	if err := projectGoals(goals); err != nil {
+		c.JSON(projectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, goals)
 }
 
+// GetGoalTemplates lists the goal templates and the inputs each one takes
+func GetGoalTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, []gin.H{
+		{
+			"template":    models.GoalTemplateEmergencyFund,
+			"description": "Months of your average spending over the last year",
+			"inputs":      gin.H{"months": 6},
+		},
+		{
+			"template":    models.GoalTemplateRetirement,
+			"description": "A corpus whose yearly withdrawal at the withdrawal rate covers a year of spending",
+			"inputs":      gin.H{"withdrawal_rate": 4, "current_age": nil, "retirement_age": nil},
+		},
+		{
+			"template":    models.GoalTemplateEducation,
+			"description": "Today's cost of a course, grown by cost inflation until the child reaches the start age",
+			"inputs":      gin.H{"child_age": nil, "start_age": 18, "cost_today": nil, "cost_inflation": 10},
+		},
+	})
+}
+
 // GetGoal retrieves a single goal by ID for the authenticated user
 func GetGoal(c *gin.Context) {
 	// Get user_id from context
@@ -62,7 +99,228 @@ func GetGoal(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, goal)
+	goals := []models.Goal{goal}
+	if err := projectGoals(goals); err != nil {
+		c.JSON(projectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, goals[0])
+}
+
+// GetGoalSimulation runs a Monte Carlo simulation of the goal's investments and
+// reports the chance of reaching the target by the deadline
+func GetGoalSimulation(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	id := c.Param("id")
+	goalID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	paths, err := strconv.Atoi(c.DefaultQuery("paths", strconv.Itoa(defaultSimulationPaths)))
+	if err != nil || paths < 1 || paths > maxSimulationPaths {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "paths must be between 1 and " + strconv.Itoa(maxSimulationPaths)})
+		return
+	}
+
+	// Without a seed one is picked and returned so that the run can be repeated
+	seed := time.Now().UnixNano()
+	if value := c.Query("seed"); value != "" {
+		if seed, err = strconv.ParseInt(value, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seed"})
+			return
+		}
+	}
+
+	var goal models.Goal
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(goalID), uint(userID)).First(&goal).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	assumptions, err := returnAssumptions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	simulation, err := services.SimulateGoal(config.DB, goal, assumptions, paths, seed, time.Now())
+	if errors.Is(err, services.ErrNoDeadline) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(projectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, simulation)
+}
+
+// GetGoalHistory retrieves the timeline of a goal's progress, milestones and status changes
+func GetGoalHistory(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	id := c.Param("id")
+	goalID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var goal models.Goal
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(goalID), uint(userID)).First(&goal).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	query := config.DB.Where("goal_id = ?", goal.ID)
+	if eventType := c.Query("type"); eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+
+	var events []models.GoalProgressEvent
+	if err := query.Order("created_at ASC, id ASC").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"goal_id": goal.ID,
+		"events":  events,
+		"count":   len(events),
+	})
+}
+
+// PlanGoalContributions splits a month's budget savings across the user's open goals
+// Query parameters:
+//   - month: budget month as "2024-01" (default: the current month)
+//   - strategy: priority, proportional or deadline (default: priority)
+//   - commit: "true" to record the split as planned contributions; otherwise only a preview is returned
+func PlanGoalContributions(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	now := time.Now()
+	month := c.DefaultQuery("month", now.Format("2006-01"))
+	if _, err := time.Parse("2006-01", month); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month, expected YYYY-MM"})
+		return
+	}
+
+	strategy := c.DefaultQuery("strategy", services.PlanStrategyPriority)
+	if !services.ValidPlanStrategy(strategy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "strategy must be one of priority, proportional or deadline"})
+		return
+	}
+
+	var budget models.Budget
+	if err := config.DB.Where("user_id = ? AND month = ?", uint(userID), month).Order("created_at DESC").First(&budget).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Budget not found for " + month})
+		return
+	}
+
+	assumptions, err := returnAssumptions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	plan, err := services.PlanSavings(config.DB, budget, strategy, assumptions, now)
+	if errors.Is(err, services.ErrNoSavings) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(projectionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Preview unless the caller asked to commit
+	if c.Query("commit") != "true" {
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+
+	contributions, err := services.CommitSavingsPlan(config.DB, uint(userID), &plan)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save planned contributions: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"plan":          plan,
+		"contributions": contributions,
+	})
+}
+
+// GetPlannedContributions retrieves the planned contributions committed for a month (default: the current month)
+func GetPlannedContributions(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	month := c.DefaultQuery("month", time.Now().Format("2006-01"))
+	if _, err := time.Parse("2006-01", month); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month, expected YYYY-MM"})
+		return
+	}
+
+	var contributions []models.PlannedContribution
+	if err := config.DB.Where("user_id = ? AND month = ?", uint(userID), month).Order("goal_id ASC").Find(&contributions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"month":         month,
+		"contributions": contributions,
+		"count":         len(contributions),
+	})
 }
 
 // CreateGoal creates a new goal
@@ -93,6 +351,25 @@ func CreateGoal(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Goal name is required"})
 		return
 	}
+	if goal.Currency, err = resolveCurrency(goal.Currency, models.DefaultCurrency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if goal.InflationRate != nil && (*goal.InflationRate < 0 || *goal.InflationRate > 50) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Inflation rate must be between 0 and 50"})
+		return
+	}
+
+	// Templates fill in the target from their inputs
+	if err := goal.ValidateTemplate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.ApplyGoalTemplate(config.DB, &goal, time.Now()); err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
 	if goal.TargetAmount <= 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Target amount must be greater than 0"})
 		return
@@ -116,6 +393,24 @@ func CreateGoal(c *gin.Context) {
 		return
 	}
 
+	// An amount already saved becomes the goal's opening deposit, so refreshes keep it
+	if goal.CurrentAmount > 0 {
+		config.DB.Create(&models.GoalContribution{
+			UserID:   goal.UserID,
+			GoalID:   goal.ID,
+			Type:     models.GoalContributionDeposit,
+			Amount:   goal.CurrentAmount,
+			Currency: goal.Currency,
+			Date:     goal.CreatedAt,
+			Notes:    "Opening balance",
+		})
+	}
+
+	// Start the goal's history; the goal is saved either way
+	if err := services.RecordGoalProgress(config.DB, nil, goal); err != nil {
+		log.Printf("❌ Failed to record history of goal %d: %v", goal.ID, err)
+	}
+
 	c.JSON(http.StatusCreated, goal)
 }
 
@@ -156,6 +451,32 @@ func UpdateGoal(c *gin.Context) {
 
 	goal.ID = uint(goalID)
 	goal.UserID = uint(userID)
+	goal.CreatedAt = existingGoal.CreatedAt         // Inflation is measured from the day the goal was set
+	goal.CurrentAmount = existingGoal.CurrentAmount // Comes from linked investments and contributions
+	if goal.Currency, err = resolveCurrency(goal.Currency, existingGoal.Currency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if goal.InflationRate != nil && (*goal.InflationRate < 0 || *goal.InflationRate > 50) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Inflation rate must be between 0 and 50"})
+		return
+	}
+
+	// Templated goals keep their template and are resized from the new inputs
+	if goal.Template == "" {
+		goal.Template = existingGoal.Template
+	}
+	if goal.TemplateInputs == nil && goal.Template == existingGoal.Template {
+		goal.TemplateInputs = existingGoal.TemplateInputs
+	}
+	if err := goal.ValidateTemplate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.ApplyGoalTemplate(config.DB, &goal, time.Now()); err != nil {
+		c.JSON(templateErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
 
 	// Update status based on progress
 	goal.UpdateStatus()
@@ -164,6 +485,17 @@ func UpdateGoal(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if err := services.RecordGoalProgress(config.DB, &existingGoal, goal); err != nil {
+		log.Printf("❌ Failed to record history of goal %d: %v", goal.ID, err)
+	}
+
+	// Linked investments are converted to the goal's currency
+	if goal.Currency != existingGoal.Currency {
+		if err := updateGoalCurrentAmount(goal.ID); err == nil {
+			config.DB.First(&goal, goal.ID)
+			goal.CalculateInflation()
+		}
+	}
 
 	c.JSON(http.StatusOK, goal)
 }
@@ -202,7 +534,51 @@ func DeleteGoal(c *gin.Context) {
 		return
 	}
 
+	// Free the goal's share of any investments allocated to it
+	if err := services.RemoveGoalAllocations(config.DB, goal.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Goal deleted successfully"})
 }
 
+// projectGoals fills in each goal's inflated target and its projection
+func projectGoals(goals []models.Goal) error {
+	for i := range goals {
+		goals[i].CalculateInflation()
+	}
+
+	assumptions, err := returnAssumptions()
+	if err != nil {
+		return err
+	}
+	return services.ProjectGoals(config.DB, goals, assumptions, time.Now())
+}
+
+// returnAssumptions returns the default expected returns and volatility,
+// overridden by the JSON file in EXPECTED_RETURNS_FILE when set
+func returnAssumptions() (services.ReturnAssumptions, error) {
+	if path := os.Getenv("EXPECTED_RETURNS_FILE"); path != "" {
+		return services.LoadReturnAssumptions(path)
+	}
+	return services.DefaultReturnAssumptions(), nil
+}
+
+// templateErrorStatus reports missing spending or exchange rates as unprocessable and anything else as a server error
+func templateErrorStatus(err error) int {
+	if errors.Is(err, services.ErrNoSpending) {
+		return http.StatusUnprocessableEntity
+	}
+	return projectionErrorStatus(err)
+}
+
+// projectionErrorStatus reports a missing exchange rate as unprocessable and anything else as a server error
+func projectionErrorStatus(err error) int {
+	if errors.Is(err, services.ErrNoFXRate) {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
 //
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBackfillDays limits how many daily snapshots a single backfill may rebuild
+const maxBackfillDays = 3 * 366
+
+// GetNetWorthHistory returns the user's portfolio snapshots between from and to
+// (YYYY-MM-DD, default the last 30 days) at a day, week or month interval
+func GetNetWorthHistory(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	from, to, err := parseDateRange(c, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	interval := c.DefaultQuery("interval", "day")
+	if interval != "day" && interval != "week" && interval != "month" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Interval must be one of day, week, month"})
+		return
+	}
+
+	var snapshots []models.PortfolioSnapshot
+	if err := config.DB.Where("user_id = ? AND date BETWEEN ? AND ?", uint(userID), from, to).Order("date ASC").Find(&snapshots).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Keep the last snapshot in each week or month
+	points := []models.PortfolioSnapshot{}
+	lastBucket := ""
+	for _, snapshot := range snapshots {
+		bucket := snapshot.Date.Format("2006-01-02")
+		switch interval {
+		case "week":
+			year, week := snapshot.Date.ISOWeek()
+			bucket = fmt.Sprintf("%d-W%02d", year, week)
+		case "month":
+			bucket = snapshot.Date.Format("2006-01")
+		}
+		if bucket == lastBucket {
+			points[len(points)-1] = snapshot
+			continue
+		}
+		points = append(points, snapshot)
+		lastBucket = bucket
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":     from.Format("2006-01-02"),
+		"to":       to.Format("2006-01-02"),
+		"interval": interval,
+		"points":   points,
+	})
+}
+
+// BackfillNetWorthHistory rebuilds the user's daily snapshots between from and to
+func BackfillNetWorthHistory(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	from, to, err := parseDateRange(c, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// Snapshots from today on are taken live, so they cannot be backfilled
+	if to.After(models.SnapshotDate(time.Now())) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The to date cannot be in the future"})
+		return
+	}
+	if to.Sub(from).Hours()/24 > maxBackfillDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Backfill range cannot exceed %d days", maxBackfillDays)})
+		return
+	}
+
+	count, err := services.BackfillSnapshots(config.DB, uint(userID), from, to, priceProvider)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to backfill snapshots: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Snapshots backfilled successfully",
+		"snapshots": count,
+	})
+}
+
+// parseDateRange reads the from and to query parameters (YYYY-MM-DD), defaulting
+// to defaultFrom and today
+func parseDateRange(c *gin.Context, defaultFrom time.Time) (time.Time, time.Time, error) {
+	from := models.SnapshotDate(defaultFrom)
+	to := models.SnapshotDate(time.Now())
+
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return from, to, errors.New("Invalid from date, expected YYYY-MM-DD")
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return from, to, errors.New("Invalid to date, expected YYYY-MM-DD")
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		return from, to, errors.New("The to date must not be before the from date")
+	}
+	return from, to, nil
+}
+
+//
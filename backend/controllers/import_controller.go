@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"encoding/json"
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportInvestments imports holdings from an uploaded broker or CAS CSV statement.
+//
+// Form fields:
+//   - file: the CSV file (required)
+//   - mapping: JSON object mapping name, type, symbol, units, invested,
+//     current_value and purchase_date to CSV column headers (optional)
+//   - date_format: Go layout of the purchase date column (optional)
+//   - commit: "true" to apply the import; otherwise only a preview is returned
+func ImportInvestments(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A CSV file is required in the \"file\" field"})
+		return
+	}
+
+	var mapping services.ImportMapping
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid column mapping: " + err.Error()})
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	rows, err := services.ParseInvestmentCSV(file, mapping, c.PostForm("date_format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CSV file: " + err.Error()})
+		return
+	}
+
+	preview, err := services.PlanInvestmentImport(config.DB, uint(userID), rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Dry run unless the caller asked to commit
+	if c.PostForm("commit") != "true" && c.Query("commit") != "true" {
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	if preview.Invalid > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Import has invalid rows; fix them before committing",
+			"preview": preview,
+		})
+		return
+	}
+
+	if err := services.CommitInvestmentImport(config.DB, uint(userID), &preview); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import investments: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, preview)
+}
+
+//
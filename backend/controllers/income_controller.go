@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetIncomeEvents retrieves the dividends, interest and coupons received from an investment
+func GetIncomeEvents(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	investmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid investment ID format"})
+		return
+	}
+
+	// Verify the investment belongs to the user
+	var investment models.Investment
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(investmentID), uint(userID)).First(&investment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Investment not found"})
+		return
+	}
+
+	var events []models.IncomeEvent
+	if err := config.DB.Where("investment_id = ?", investment.ID).Order("date DESC").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"income_events": events,
+		"total":         investment.Income,
+		"count":         len(events),
+	})
+}
+
+// CreateIncomeEvent records a payout received from an investment
+func CreateIncomeEvent(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	investmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid investment ID format"})
+		return
+	}
+
+	var investment models.Investment
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(investmentID), uint(userID)).First(&investment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Investment not found"})
+		return
+	}
+
+	var event models.IncomeEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+		return
+	}
+
+	event.ID = 0
+	event.UserID = uint(userID)
+	event.InvestmentID = investment.ID
+
+	if err := event.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := config.DB.Create(&event).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create income event: " + err.Error()})
+		return
+	}
+
+	if err := refreshInvestmentIncome(&investment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"income_event": event,
+		"investment":   investment,
+	})
+}
+
+// DeleteIncomeEvent removes a payout from an investment
+func DeleteIncomeEvent(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	investmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid investment ID format"})
+		return
+	}
+
+	eventID, err := strconv.ParseUint(c.Param("income_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid income event ID format"})
+		return
+	}
+
+	var investment models.Investment
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(investmentID), uint(userID)).First(&investment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Investment not found"})
+		return
+	}
+
+	var event models.IncomeEvent
+	if err := config.DB.Where("id = ? AND investment_id = ?", uint(eventID), investment.ID).First(&event).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Income event not found"})
+		return
+	}
+
+	if err := config.DB.Delete(&event).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := refreshInvestmentIncome(&investment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Income event deleted successfully",
+		"investment": investment,
+	})
+}
+
+// refreshInvestmentIncome recalculates and saves an investment's income and total returns
+func refreshInvestmentIncome(investment *models.Investment) error {
+	if err := services.LoadInvestmentIncome(config.DB, investment); err != nil {
+		return err
+	}
+
+	return config.DB.Model(investment).Updates(map[string]interface{}{
+		"income":        investment.Income,
+		"total_returns": investment.TotalReturns,
+	}).Error
+}
+
+//
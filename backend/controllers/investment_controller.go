@@ -1,8 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"investment-tracker-backend/config"
+	"investment-tracker-backend/finance"
 	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"io"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
@@ -31,9 +36,62 @@ func GetInvestments(c *gin.Context) {
 		return
 	}
 
+	if _, err := annualizeInvestments(investments, ""); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, investments)
 }
 
+// GetMaturingDeposits retrieves deposits maturing within the next ?days= days (default 30)
+func GetMaturingDeposits(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Days must be a positive number"})
+		return
+	}
+
+	now := time.Now()
+	var deposits []models.Investment
+	if err := config.DB.Where("user_id = ? AND maturity_date BETWEEN ? AND ?", uint(userID), now, now.AddDate(0, 0, days)).
+		Order("maturity_date ASC").Find(&deposits).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	type maturingDeposit struct {
+		models.Investment
+		DaysToMaturity int `json:"days_to_maturity"`
+	}
+	maturing := make([]maturingDeposit, len(deposits))
+	for i, deposit := range deposits {
+		maturing[i] = maturingDeposit{
+			Investment:     deposit,
+			DaysToMaturity: int(deposit.MaturityDate.Sub(now).Hours() / 24),
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deposits": maturing,
+		"count":    len(maturing),
+		"days":     days,
+	})
+}
+
 // GetInvestment retrieves a single investment by ID for the authenticated user
 func GetInvestment(c *gin.Context) {
 	// Get user_id from context
@@ -62,6 +120,13 @@ func GetInvestment(c *gin.Context) {
 		return
 	}
 
+	var transactions []models.InvestmentTransaction
+	if err := config.DB.Where("investment_id = ?", investment.ID).Find(&transactions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	investment.CalculateAnnualizedReturns(transactions, time.Now())
+
 	c.JSON(http.StatusOK, investment)
 }
 
@@ -103,21 +168,50 @@ func CreateInvestment(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invested amount must be greater than 0"})
 		return
 	}
+	if investment.Currency, err = resolveCurrency(investment.Currency, models.DefaultCurrency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Set default purchase date if not provided
 	if investment.PurchaseDate.IsZero() {
 		investment.PurchaseDate = time.Now()
 	}
 
+	// Deposits derive their value from the interest rate and tenure
+	investment.AccrueDeposit(time.Now())
+
 	// Calculate returns and status
 	investment.CalculateReturns()
-	investment.UpdateStatus()
+	if err := services.UpdateInvestmentStatus(config.DB, &investment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// A goal given on creation gets the whole investment
+	goalID := investment.GoalID
+	investment.GoalID = nil
+	if goalID != nil {
+		var goal models.Goal
+		if err := config.DB.Where("id = ? AND user_id = ?", *goalID, uint(userID)).First(&goal).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+			return
+		}
+	}
 
 	if err := config.DB.Create(&investment).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create investment: " + err.Error()})
 		return
 	}
 
+	if goalID != nil {
+		allocations := []models.InvestmentGoalAllocation{{GoalID: *goalID, Percent: 100}}
+		if err := services.SaveGoalAllocations(config.DB, &investment, allocations); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	c.JSON(http.StatusCreated, investment)
 }
 
@@ -157,22 +251,61 @@ func UpdateInvestment(c *gin.Context) {
 	}
 
 	investment.ID = uint(investmentID)
+	investment.UserID = uint(userID)
+	investment.Income = oldInvestment.Income       // Maintained from income events
+	investment.PeakValue = oldInvestment.PeakValue // Tracked for drawdown rules
+
+	// Goal links are kept unless a different goal is given, which then gets the whole investment
+	goalChanged := investment.GoalID != nil && (oldInvestment.GoalID == nil || *oldInvestment.GoalID != *investment.GoalID)
+	if goalChanged {
+		var goal models.Goal
+		if err := config.DB.Where("id = ? AND user_id = ?", *investment.GoalID, uint(userID)).First(&goal).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+			return
+		}
+	}
+	newGoalID := investment.GoalID
+	investment.GoalID = oldInvestment.GoalID
+	if investment.Currency, err = resolveCurrency(investment.Currency, oldInvestment.Currency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Holdings with a transaction ledger derive invested amount and units from it
+	var transactions []models.InvestmentTransaction
+	if err := config.DB.Where("investment_id = ?", investment.ID).Find(&transactions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(transactions) > 0 {
+		investment.Units = oldInvestment.Units
+		investment.ApplyLedger(transactions)
+	}
+
+	// Deposits derive their value from the interest rate and tenure
+	investment.AccrueDeposit(time.Now())
 
 	// Recalculate returns and status
 	investment.CalculateReturns()
-	investment.UpdateStatus()
+	if err := services.UpdateInvestmentStatus(config.DB, &investment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	if err := config.DB.Save(&investment).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Update goal current_amount if goal_id changed or current_value changed
-	if oldInvestment.GoalID != nil {
-		updateGoalCurrentAmount(*oldInvestment.GoalID)
-	}
-	if investment.GoalID != nil && (oldInvestment.GoalID == nil || *oldInvestment.GoalID != *investment.GoalID) {
-		updateGoalCurrentAmount(*investment.GoalID)
+	// Update goal current_amount if the goal changed or current_value changed
+	if goalChanged {
+		allocations := []models.InvestmentGoalAllocation{{GoalID: *newGoalID, Percent: 100}}
+		if err := services.SaveGoalAllocations(config.DB, &investment, allocations); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		services.RefreshInvestmentGoals(config.DB, investment.ID)
 	}
 
 	c.JSON(http.StatusOK, investment)
@@ -207,23 +340,20 @@ func DeleteInvestment(c *gin.Context) {
 		return
 	}
 
-	// Store the goal_id before deletion
-	goalID := investment.GoalID
-
 	if err := config.DB.Delete(&investment).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Update the goal's current_amount if the investment was linked to a goal
-	if goalID != nil {
-		updateGoalCurrentAmount(*goalID)
-	}
+	// Drop the investment's goal allocations and update those goals' current_amount
+	services.RemoveInvestmentAllocations(config.DB, investment.ID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Investment deleted successfully"})
 }
 
-// LinkInvestmentToGoal links an investment to a goal
+// LinkInvestmentToGoal allocates an investment to a goal. Without a percent or
+// amount the whole investment moves to the goal; with one, that share of it is
+// allocated alongside the investment's other goals.
 func LinkInvestmentToGoal(c *gin.Context) {
 	// Get user_id from context
 	userIDStr, exists := c.Get("user_id")
@@ -246,7 +376,9 @@ func LinkInvestmentToGoal(c *gin.Context) {
 	}
 
 	var requestBody struct {
-		GoalID string `json:"goal_id"`
+		GoalID  string        `json:"goal_id"`
+		Percent *float64      `json:"percent"` // Share of the investment to allocate
+		Amount  *models.Money `json:"amount"`  // Or an amount of its current value
 	}
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
@@ -260,72 +392,64 @@ func LinkInvestmentToGoal(c *gin.Context) {
 		return
 	}
 
-	var goalIDPtr *uint
-	var newGoalID uint
-	if requestBody.GoalID != "" {
+	allocations, err := investmentAllocations(investment.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if requestBody.GoalID == "" {
+		// No goal unlinks the investment from all goals
+		allocations = nil
+	} else {
 		gid, err := strconv.ParseUint(requestBody.GoalID, 10, 32)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID format"})
 			return
 		}
-		goalIDUint := uint(gid)
-		goalIDPtr = &goalIDUint
-		newGoalID = goalIDUint
 
 		// Verify goal exists and belongs to the user
 		var goal models.Goal
-		if err := config.DB.Where("id = ? AND user_id = ?", goalIDUint, uint(userID)).First(&goal).Error; err != nil {
+		if err := config.DB.Where("id = ? AND user_id = ?", uint(gid), uint(userID)).First(&goal).Error; err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
 			return
 		}
-	}
-
-	// Update investment with goal_id
-	investment.GoalID = goalIDPtr
 
-	if err := config.DB.Save(&investment).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		if requestBody.Percent == nil && requestBody.Amount == nil {
+			allocations = []models.InvestmentGoalAllocation{{GoalID: goal.ID, Percent: 100}}
+		} else {
+			percent, err := allocationPercent(investment, requestBody.Percent, requestBody.Amount)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			allocations = withAllocation(allocations, goal.ID, percent)
+		}
 	}
 
-	// Update the old goal's current_amount (if there was one)
-	if investment.GoalID != nil {
-		updateGoalCurrentAmount(*investment.GoalID)
+	if err := models.ValidateAllocations(allocations); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-
-	// Update the new goal's current_amount (if linking to a goal)
-	if newGoalID != 0 {
-		updateGoalCurrentAmount(newGoalID)
+	if err := services.SaveGoalAllocations(config.DB, &investment, allocations); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Investment linked to goal successfully",
-		"goal_id": requestBody.GoalID,
+		"message":     "Investment linked to goal successfully",
+		"goal_id":     requestBody.GoalID,
+		"allocations": allocations,
 	})
 }
 
-// updateGoalCurrentAmount recalculates and updates a goal's current_amount based on linked investments
+// updateGoalCurrentAmount recalculates and updates a goal's current_amount from linked investments and contributions
 func updateGoalCurrentAmount(goalID uint) error {
-	// Find all investments linked to this goal
-	var investments []models.Investment
-	if err := config.DB.Where("goal_id = ?", goalID).Find(&investments).Error; err != nil {
-		return err
-	}
-
-	// Calculate total current value
-	totalCurrentValue := 0.0
-	for _, inv := range investments {
-		totalCurrentValue += inv.CurrentValue
-	}
-
-	// Update the goal's current_amount
-	return config.DB.Model(&models.Goal{}).Where("id = ?", goalID).Updates(map[string]interface{}{
-		"current_amount": totalCurrentValue,
-		"updated_at":     time.Now(),
-	}).Error
+	return services.RefreshGoalCurrentAmount(config.DB, goalID)
 }
 
-// UnlinkInvestmentFromGoal removes the goal link from an investment
+// UnlinkInvestmentFromGoal removes an investment's goal allocations. A goal_id
+// limits it to that goal, and a percent or amount only reduces that goal's share.
 func UnlinkInvestmentFromGoal(c *gin.Context) {
 	// Get user_id from context
 	userIDStr, exists := c.Get("user_id")
@@ -347,6 +471,17 @@ func UnlinkInvestmentFromGoal(c *gin.Context) {
 		return
 	}
 
+	// The body is optional; an empty one unlinks every goal
+	var requestBody struct {
+		GoalID  string        `json:"goal_id"`
+		Percent *float64      `json:"percent"`
+		Amount  *models.Money `json:"amount"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		return
+	}
+
 	// Get the investment and verify ownership
 	var investment models.Investment
 	if err := config.DB.Where("id = ? AND user_id = ?", uint(investmentIDUint), uint(userID)).First(&investment).Error; err != nil {
@@ -354,23 +489,100 @@ func UnlinkInvestmentFromGoal(c *gin.Context) {
 		return
 	}
 
-	// Store the old goal_id before unlinking
-	oldGoalID := investment.GoalID
+	allocations, err := investmentAllocations(investment.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	// Update investment to remove goal_id
-	investment.GoalID = nil
+	if requestBody.GoalID == "" {
+		allocations = nil
+	} else {
+		gid, err := strconv.ParseUint(requestBody.GoalID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID format"})
+			return
+		}
+		goalID := uint(gid)
 
-	if err := config.DB.Save(&investment).Error; err != nil {
+		var current float64
+		for _, a := range allocations {
+			if a.GoalID == goalID {
+				current = a.Percent
+			}
+		}
+		if current == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Investment is not linked to this goal"})
+			return
+		}
+
+		remaining := 0.0
+		if requestBody.Percent != nil || requestBody.Amount != nil {
+			percent, err := allocationPercent(investment, requestBody.Percent, requestBody.Amount)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			remaining = math.Max(models.Round2(current-percent), 0)
+		}
+		allocations = withAllocation(allocations, goalID, remaining)
+	}
+
+	if err := services.SaveGoalAllocations(config.DB, &investment, allocations); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Update the old goal's current_amount
-	if oldGoalID != nil {
-		updateGoalCurrentAmount(*oldGoalID)
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Investment unlinked from goal successfully",
+		"allocations": allocations,
+	})
+}
+
+// investmentAllocations fetches an investment's goal allocations
+func investmentAllocations(investmentID uint) ([]models.InvestmentGoalAllocation, error) {
+	byInvestment, err := services.LoadGoalAllocations(config.DB, []uint{investmentID})
+	if err != nil {
+		return nil, err
 	}
+	return byInvestment[investmentID], nil
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Investment unlinked from goal successfully"})
+// allocationPercent turns a requested percent, or an amount of the investment's
+// current value, into a percentage of the investment
+func allocationPercent(investment models.Investment, percent *float64, amount *models.Money) (float64, error) {
+	if percent != nil {
+		if *percent <= 0 || *percent > 100 {
+			return 0, errors.New("Percent must be greater than 0 and at most 100")
+		}
+		return *percent, nil
+	}
+
+	if *amount <= 0 {
+		return 0, errors.New("Amount must be greater than 0")
+	}
+	if investment.CurrentValue <= 0 {
+		return 0, errors.New("Cannot allocate an amount of an investment with no current value")
+	}
+	if *amount > investment.CurrentValue {
+		return 0, errors.New("Amount cannot exceed the investment's current value")
+	}
+	return math.Round(amount.Ratio(investment.CurrentValue)*10000) / 100, nil
+}
+
+// withAllocation sets a goal's percent in a list of allocations, removing it
+// when percent is 0
+func withAllocation(allocations []models.InvestmentGoalAllocation, goalID uint, percent float64) []models.InvestmentGoalAllocation {
+	result := make([]models.InvestmentGoalAllocation, 0, len(allocations)+1)
+	for _, a := range allocations {
+		if a.GoalID != goalID {
+			result = append(result, a)
+		}
+	}
+	if percent > 0 {
+		result = append(result, models.InvestmentGoalAllocation{GoalID: goalID, Percent: percent})
+	}
+	return result
 }
 
 // GetInvestmentsByGoal retrieves all investments linked to a specific goal
@@ -402,24 +614,127 @@ func GetInvestmentsByGoal(c *gin.Context) {
 		return
 	}
 
-	// Get investments for this goal (they should all belong to the user since the goal does)
-	var investments []models.Investment
-	if err := config.DB.Where("goal_id = ? AND user_id = ?", uint(goalIDUint), uint(userID)).Find(&investments).Error; err != nil {
+	// Get investments allocated to this goal (they should all belong to the user since the goal does)
+	var allocations []models.InvestmentGoalAllocation
+	if err := config.DB.Where("goal_id = ? AND user_id = ?", goal.ID, uint(userID)).Find(&allocations).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	investmentIDs := make([]uint, len(allocations))
+	for i, a := range allocations {
+		investmentIDs[i] = a.InvestmentID
+	}
+	investments := []models.Investment{}
+	if len(investmentIDs) > 0 {
+		if err := config.DB.Where("id IN ? AND user_id = ?", investmentIDs, uint(userID)).Find(&investments).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
-	// Calculate total
-	total := 0.0
+	// Calculate the goal's share in the goal's currency. Holdings without an
+	// exchange rate are left out and their currency pairs reported.
+	fx := services.NewFXConverter(config.DB)
+	converted := make([]models.Investment, 0, len(investments))
 	for _, inv := range investments {
-		total += inv.CurrentValue
+		value, err := fx.Convert(inv.CurrentValue, inv.Currency, goal.Currency, time.Now())
+		if errors.Is(err, services.ErrNoFXRate) {
+			continue
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		inv.CurrentValue = value
+		converted = append(converted, inv)
+	}
+	investmentsTotal := goal.CalculateLinkedInvestmentsTotal(converted, allocations)
+
+	// Manual contributions count on top of the investments, as in the goal's current_amount
+	contributionsTotal, err := services.GoalContributionsTotal(config.DB, fx, goal, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	total := investmentsTotal + contributionsTotal
+	if total < 0 {
+		total = 0
 	}
 
+	// Annualized returns per investment and for the goal as a whole
+	flows, err := annualizeInvestments(investments, goal.Currency)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	xirr, cagr := models.AnnualizedReturns(flows, time.Now())
+
 	c.JSON(http.StatusOK, gin.H{
-		"investments": investments,
-		"total":       total,
-		"count":       len(investments),
+		"investments":         investments,
+		"allocations":         allocations,
+		"investments_total":   investmentsTotal,
+		"contributions_total": contributionsTotal,
+		"total":               total,
+		"count":               len(investments),
+		"currency":            models.NormalizeCurrency(goal.Currency),
+		"xirr":                xirr,
+		"cagr":                cagr,
+		"missing_fx_rates":    fx.Missing(),
 	})
 }
 
+// annualizeInvestments fills in XIRR and CAGR on each investment and returns
+// their combined cash flows for portfolio-level figures, converted to currency
+// unless it is empty. Investments without an exchange rate are left out of the
+// combined flows.
+func annualizeInvestments(investments []models.Investment, currency string) ([]finance.CashFlow, error) {
+	byInvestment, err := loadLedgers(investments)
+	if err != nil {
+		return nil, err
+	}
+
+	fx := services.NewFXConverter(config.DB)
+	now := time.Now()
+	var flows []finance.CashFlow
+	for i := range investments {
+		ledger := byInvestment[investments[i].ID]
+		investments[i].CalculateAnnualizedReturns(ledger, now)
+
+		investmentFlows := investments[i].CashFlows(ledger, now)
+		if currency != "" {
+			investmentFlows, err = fx.ConvertFlows(investmentFlows, investments[i].Currency, currency)
+			if errors.Is(err, services.ErrNoFXRate) {
+				continue // Left out of the combined flows
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		flows = append(flows, investmentFlows...)
+	}
+	return flows, nil
+}
+
+// loadLedgers fetches the transactions of each investment, keyed by investment ID
+func loadLedgers(investments []models.Investment) (map[uint][]models.InvestmentTransaction, error) {
+	byInvestment := make(map[uint][]models.InvestmentTransaction)
+	if len(investments) == 0 {
+		return byInvestment, nil
+	}
+
+	ids := make([]uint, len(investments))
+	for i, inv := range investments {
+		ids[i] = inv.ID
+	}
+
+	var transactions []models.InvestmentTransaction
+	if err := config.DB.Where("investment_id IN ?", ids).Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range transactions {
+		byInvestment[t.InvestmentID] = append(byInvestment[t.InvestmentID], t)
+	}
+	return byInvestment, nil
+}
+
 //
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/reports"
+	"investment-tracker-backend/services"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// defaultAllocationTolerance is the drift, in percentage points, allowed before rebalancing
+const defaultAllocationTolerance = 5.0
+
+// GetAllocation returns the portfolio's asset allocation against the user's
+// targets with buy/sell amounts when it drifts outside ?tolerance= (default 5)
+func GetAllocation(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	tolerance := defaultAllocationTolerance
+	if value := c.Query("tolerance"); value != "" {
+		tolerance, err = strconv.ParseFloat(value, 64)
+		if err != nil || tolerance < 0 || tolerance > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tolerance must be a number between 0 and 100"})
+			return
+		}
+	}
+
+	// Values are compared in the user's base currency at today's rates
+	var user models.User
+	if err := config.DB.Select("id", "base_currency").First(&user, uint(userID)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	base := models.NormalizeCurrency(user.BaseCurrency)
+
+	var investments []models.Investment
+	if err := config.DB.Where("user_id = ?", uint(userID)).Find(&investments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Holdings without a rate are left out and reported
+	fx := services.NewFXConverter(config.DB)
+	now := time.Now()
+	converted := make([]models.Investment, 0, len(investments))
+	for _, inv := range investments {
+		value, err := fx.Convert(inv.CurrentValue, inv.Currency, base, now)
+		if errors.Is(err, services.ErrNoFXRate) {
+			continue
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		inv.CurrentValue = value
+		converted = append(converted, inv)
+	}
+
+	var targets []models.AllocationTarget
+	if err := config.DB.Where("user_id = ?", uint(userID)).Find(&targets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	targetPercents := make(map[string]float64)
+	for _, target := range targets {
+		targetPercents[target.AssetClass] = target.Percent
+	}
+
+	report := reports.BuildAllocation(converted, targetPercents, tolerance)
+	report.Currency = base
+	report.MissingFXRates = fx.Missing()
+	c.JSON(http.StatusOK, report)
+}
+
+// GetAllocationTargets retrieves the user's target allocation
+func GetAllocationTargets(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var targets []models.AllocationTarget
+	if err := config.DB.Where("user_id = ?", uint(userID)).Find(&targets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"targets":       targets,
+		"asset_classes": models.AssetClasses,
+	})
+}
+
+// SetAllocationTargets replaces the user's target allocation. Percentages must add up to 100.
+func SetAllocationTargets(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var requestBody struct {
+		Targets []models.AllocationTarget `json:"targets"`
+	}
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+		return
+	}
+
+	// Manual validation
+	total := 0.0
+	seen := make(map[string]bool)
+	for _, target := range requestBody.Targets {
+		if !models.IsAssetClass(target.AssetClass) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown asset class %q", target.AssetClass), "asset_classes": models.AssetClasses})
+			return
+		}
+		if seen[target.AssetClass] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Asset class %q is listed more than once", target.AssetClass)})
+			return
+		}
+		if target.Percent < 0 || target.Percent > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Target percentages must be between 0 and 100"})
+			return
+		}
+		seen[target.AssetClass] = true
+		total += target.Percent
+	}
+	if len(requestBody.Targets) > 0 && math.Abs(total-100) > 0.01 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Target percentages must add up to 100, got %.2f", total)})
+		return
+	}
+
+	targets := make([]models.AllocationTarget, len(requestBody.Targets))
+	for i, target := range requestBody.Targets {
+		targets[i] = models.AllocationTarget{UserID: uint(userID), AssetClass: target.AssetClass, Percent: target.Percent}
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", uint(userID)).Delete(&models.AllocationTarget{}).Error; err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+		return tx.Create(&targets).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save allocation targets: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"targets": targets})
+}
+
+//
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/pricing"
+	"investment-tracker-backend/services"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// priceProvider supplies historical prices, or nil when none is configured
+var priceProvider pricing.PriceProvider
+
+// InitPriceProvider sets the price provider used by handlers that need market prices
+func InitPriceProvider(provider pricing.PriceProvider) {
+	priceProvider = provider
+}
+
+// ImportAMFINAV revalues mutual fund holdings from an uploaded AMFI NAVAll.txt file.
+// The file can be sent as a multipart "file" field or as the raw request body.
+func ImportAMFINAV(c *gin.Context) {
+	var reader io.Reader = c.Request.Body
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	navs, err := pricing.ParseAMFINAV(reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid NAV file: " + err.Error()})
+		return
+	}
+
+	summary, err := services.ImportAMFINAV(config.DB, navs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import NAVs: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+//
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/reports"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCapitalGainsReport returns realized and unrealized gains for a financial
+// year (?fy=2025-26, default the current one), split into short and long term.
+// Pass ?format=csv for a CSV download.
+func GetCapitalGainsReport(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	now := time.Now()
+	fy := c.DefaultQuery("fy", reports.CurrentFinancialYear(now))
+	from, to, err := reports.ParseFinancialYear(fy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Holding periods can be overridden per type or asset class with a JSON file
+	rules := reports.DefaultHoldingRules()
+	if path := os.Getenv("CAPITAL_GAINS_RULES_FILE"); path != "" {
+		if rules, err = reports.LoadHoldingRules(path); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var investments []models.Investment
+	if err := config.DB.Where("user_id = ?", uint(userID)).Find(&investments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var transactions []models.InvestmentTransaction
+	if err := config.DB.Where("user_id = ?", uint(userID)).Find(&transactions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ledgers := make(map[uint][]models.InvestmentTransaction)
+	for _, t := range transactions {
+		ledgers[t.InvestmentID] = append(ledgers[t.InvestmentID], t)
+	}
+
+	report := reports.BuildCapitalGains(fy, from, to, now, investments, ledgers, rules)
+
+	if c.Query("format") == "csv" {
+		c.Header("Content-Disposition", "attachment; filename=capital-gains-"+fy+".csv")
+		c.Header("Content-Type", "text/csv")
+		if err := report.WriteCSV(c.Writer); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+//
@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/models"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUpcomingMonths caps how far ahead upcoming installments are listed
+const maxUpcomingMonths = 24
+
+// UpcomingInstallment is a SIP installment due in the future
+type UpcomingInstallment struct {
+	SIPID          uint         `json:"sip_id"`
+	InvestmentID   uint         `json:"investment_id"`
+	InvestmentName string       `json:"investment_name"`
+	Date           time.Time    `json:"date"`
+	Amount         models.Money `json:"amount"`
+}
+
+// GetSIPs retrieves the SIPs of the authenticated user, optionally for one ?investment_id=
+func GetSIPs(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	query := config.DB.Where("user_id = ?", uint(userID))
+	if value := c.Query("investment_id"); value != "" {
+		investmentID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid investment ID format"})
+			return
+		}
+		query = query.Where("investment_id = ?", uint(investmentID))
+	}
+
+	var sips []models.SIP
+	if err := query.Order("start_date ASC").Find(&sips).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sips)
+}
+
+// GetUpcomingSIPs lists the installments due over the next ?months= months (default 3)
+func GetUpcomingSIPs(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	months, err := strconv.Atoi(c.DefaultQuery("months", "3"))
+	if err != nil || months < 1 || months > maxUpcomingMonths {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Months must be a number between 1 and 24"})
+		return
+	}
+
+	var sips []models.SIP
+	if err := config.DB.Preload("Investment").Where("user_id = ? AND active = ?", uint(userID), true).Find(&sips).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	today := models.SnapshotDate(time.Now())
+	until := today.AddDate(0, months, 0)
+
+	installments := []UpcomingInstallment{}
+	var total models.Money
+	for _, sip := range sips {
+		// Installments due today or earlier are posted by the scheduler
+		if sip.LastPostedOn == nil || sip.LastPostedOn.Before(today) {
+			posted := today
+			sip.LastPostedOn = &posted
+		}
+		for _, date := range sip.DueDates(until) {
+			installment := UpcomingInstallment{
+				SIPID:        sip.ID,
+				InvestmentID: sip.InvestmentID,
+				Date:         date,
+				Amount:       sip.Amount,
+			}
+			if sip.Investment != nil {
+				installment.InvestmentName = sip.Investment.Name
+			}
+			installments = append(installments, installment)
+			total += sip.Amount
+		}
+	}
+	sort.SliceStable(installments, func(a, b int) bool {
+		return installments[a].Date.Before(installments[b].Date)
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"installments": installments,
+		"total":        total,
+		"count":        len(installments),
+		"from":         today,
+		"to":           until,
+	})
+}
+
+// CreateSIP sets up a recurring contribution to one of the user's investments
+func CreateSIP(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var sip models.SIP
+	if err := c.ShouldBindJSON(&sip); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+		return
+	}
+
+	sip.ID = 0
+	sip.UserID = uint(userID)
+	sip.Active = true
+
+	// Verify the investment belongs to the user
+	var investment models.Investment
+	if err := config.DB.Where("id = ? AND user_id = ?", sip.InvestmentID, uint(userID)).First(&investment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Investment not found"})
+		return
+	}
+
+	if err := sip.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := config.DB.Create(&sip).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create SIP: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sip)
+}
+
+// UpdateSIP updates a SIP's amount, schedule or active flag
+func UpdateSIP(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	sipID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var existing models.SIP
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(sipID), uint(userID)).First(&existing).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SIP not found"})
+		return
+	}
+
+	// Fields left out of the request keep their current values
+	sip := existing
+	if err := c.ShouldBindJSON(&sip); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+		return
+	}
+
+	sip.ID = existing.ID
+	sip.UserID = existing.UserID
+	sip.InvestmentID = existing.InvestmentID
+
+	if err := sip.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := config.DB.Save(&sip).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sip)
+}
+
+// DeleteSIP stops and removes a SIP. Installments already posted are kept.
+func DeleteSIP(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	sipID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var sip models.SIP
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(sipID), uint(userID)).First(&sip).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "SIP not found"})
+		return
+	}
+
+	if err := config.DB.Delete(&sip).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "SIP deleted successfully"})
+}
+
+//
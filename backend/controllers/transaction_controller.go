@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"investment-tracker-backend/config"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetInvestmentTransactions retrieves the transaction ledger of an investment
+func GetInvestmentTransactions(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	investmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid investment ID format"})
+		return
+	}
+
+	// Verify the investment belongs to the user
+	var investment models.Investment
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(investmentID), uint(userID)).First(&investment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Investment not found"})
+		return
+	}
+
+	var transactions []models.InvestmentTransaction
+	if err := config.DB.Where("investment_id = ?", investment.ID).Order("date ASC, id ASC").Find(&transactions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"investment":   investment,
+		"transactions": transactions,
+		"count":        len(transactions),
+	})
+}
+
+// CreateInvestmentTransaction records a buy, sell, dividend, fee or split against an investment
+func CreateInvestmentTransaction(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	investmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid investment ID format"})
+		return
+	}
+
+	var investment models.Investment
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(investmentID), uint(userID)).First(&investment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Investment not found"})
+		return
+	}
+
+	var transaction models.InvestmentTransaction
+	if err := c.ShouldBindJSON(&transaction); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+		return
+	}
+
+	transaction.ID = 0
+	transaction.UserID = uint(userID)
+	transaction.InvestmentID = investment.ID
+
+	if err := transaction.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Sales and splits change the units held from their date on, so no sale,
+	// this one or a later one, may end up selling more than is held
+	if transaction.Type == models.TransactionSell || transaction.Type == models.TransactionSplit {
+		var existing []models.InvestmentTransaction
+		if err := config.DB.Where("investment_id = ?", investment.ID).Find(&existing).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if err := models.CheckLedger(append(existing, transaction)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := config.DB.Create(&transaction).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transaction: " + err.Error()})
+		return
+	}
+
+	if err := recalculateFromLedger(&investment, transaction.Type == models.TransactionSplit); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"transaction": transaction,
+		"investment":  investment,
+	})
+}
+
+// DeleteInvestmentTransaction removes a transaction from an investment's ledger
+func DeleteInvestmentTransaction(c *gin.Context) {
+	// Get user_id from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(userIDStr.(string), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	investmentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid investment ID format"})
+		return
+	}
+
+	transactionID, err := strconv.ParseUint(c.Param("transaction_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID format"})
+		return
+	}
+
+	var investment models.Investment
+	if err := config.DB.Where("id = ? AND user_id = ?", uint(investmentID), uint(userID)).First(&investment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Investment not found"})
+		return
+	}
+
+	var transaction models.InvestmentTransaction
+	if err := config.DB.Where("id = ? AND investment_id = ?", uint(transactionID), investment.ID).First(&transaction).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transaction not found"})
+		return
+	}
+
+	// Removing a buy or split leaves fewer units for the sales after it
+	if transaction.Type == models.TransactionBuy || transaction.Type == models.TransactionSplit {
+		var remaining []models.InvestmentTransaction
+		if err := config.DB.Where("investment_id = ? AND id <> ?", investment.ID, transaction.ID).Find(&remaining).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if err := models.CheckLedger(remaining); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot delete this transaction: " + err.Error()})
+			return
+		}
+	}
+
+	if err := config.DB.Delete(&transaction).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := recalculateFromLedger(&investment, transaction.Type == models.TransactionSplit); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Transaction deleted successfully",
+		"investment": investment,
+	})
+}
+
+// recalculateFromLedger re-derives an investment's holdings from its transactions,
+// saves it and refreshes the goals it is allocated to. Splits change the units held but
+// not the market value, so keepValue leaves the current value as it is.
+func recalculateFromLedger(investment *models.Investment, keepValue bool) error {
+	value := investment.CurrentValue
+
+	var transactions []models.InvestmentTransaction
+	if err := config.DB.Where("investment_id = ?", investment.ID).Find(&transactions).Error; err != nil {
+		return err
+	}
+
+	if len(transactions) == 0 {
+		// With the ledger emptied, the manually entered amounts are kept and
+		// returns are recalculated from them
+		investment.Units = 0
+		investment.RealizedGain = 0
+		investment.CalculateReturns()
+	} else {
+		investment.ApplyLedger(transactions)
+	}
+	if keepValue {
+		investment.CurrentValue = value
+		investment.CalculateReturns()
+	}
+
+	// Ledger dividends count towards income
+	if err := services.LoadInvestmentIncome(config.DB, investment); err != nil {
+		return err
+	}
+	if err := services.UpdateInvestmentStatus(config.DB, investment); err != nil {
+		return err
+	}
+
+	if err := config.DB.Save(investment).Error; err != nil {
+		return err
+	}
+
+	_, err := services.RefreshInvestmentGoals(config.DB, investment.ID)
+	return err
+}
+
+//
@@ -4,8 +4,11 @@ import (
 	"investment-tracker-backend/config"
 	"investment-tracker-backend/middleware"
 	"investment-tracker-backend/models"
+	"investment-tracker-backend/services"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -38,9 +41,10 @@ func UpdateUserFinancials(c *gin.Context) {
 	}
 
 	var updateData struct {
-		MonthlyIncome   *float64 `json:"monthly_income"`
-		MonthlyExpenses *float64 `json:"monthly_expenses"`
-		MonthlySavings  *float64 `json:"monthly_savings"`
+		MonthlyIncome   *models.Money `json:"monthly_income"`
+		MonthlyExpenses *models.Money `json:"monthly_expenses"`
+		MonthlySavings  *models.Money `json:"monthly_savings"`
+		BaseCurrency    *string       `json:"base_currency"`
 	}
 
 	if err := c.ShouldBindJSON(&updateData); err != nil {
@@ -65,6 +69,12 @@ func UpdateUserFinancials(c *gin.Context) {
 	if updateData.MonthlySavings != nil {
 		user.MonthlySavings = *updateData.MonthlySavings
 	}
+	if updateData.BaseCurrency != nil {
+		if user.BaseCurrency, err = resolveCurrency(*updateData.BaseCurrency, models.DefaultCurrency); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
 	// Save updates
 	if err := config.DB.Save(&user).Error; err != nil {
@@ -72,6 +82,13 @@ func UpdateUserFinancials(c *gin.Context) {
 		return
 	}
 
+	// Goals sized from spending fall back to the monthly expenses figure
+	if updateData.MonthlyExpenses != nil {
+		if err := services.RefreshTemplateGoals(config.DB, user.ID, time.Now()); err != nil {
+			log.Printf("❌ Failed to resize templated goals of user %d: %v", user.ID, err)
+		}
+	}
+
 	c.JSON(http.StatusOK, user)
 }
 
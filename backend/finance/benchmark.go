@@ -0,0 +1,49 @@
+package finance
+
+import (
+	"sort"
+	"time"
+)
+
+// PricePoint is a closing price on a day
+type PricePoint struct {
+	Date  time.Time
+	Close float64
+}
+
+// PriceSeries is a price history sorted by date
+type PriceSeries []PricePoint
+
+// PriceOn returns the latest close on or before the date
+func (s PriceSeries) PriceOn(date time.Time) (float64, bool) {
+	idx := sort.Search(len(s), func(i int) bool {
+		return s[i].Date.After(date)
+	})
+	if idx == 0 || s[idx-1].Close <= 0 {
+		return 0, false
+	}
+	return s[idx-1].Close, true
+}
+
+// ReplicateFlows puts the same cash flows into a benchmark: money paid in buys
+// units at that day's close and money taken out sells them. It returns the
+// benchmark's value at asOf, or false when the series does not cover every
+// flow.
+func ReplicateFlows(flows []CashFlow, series PriceSeries, asOf time.Time) (float64, bool) {
+	units := 0.0
+	for _, f := range sortedFlows(flows) {
+		price, ok := series.PriceOn(f.Date)
+		if !ok {
+			return 0, false
+		}
+		units -= f.Amount / price
+	}
+
+	price, ok := series.PriceOn(asOf)
+	if !ok {
+		return 0, false
+	}
+	return units * price, true
+}
+
+//
@@ -0,0 +1,60 @@
+package finance
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// CompoundingPeriods returns how many times a year interest is compounded
+// for a frequency name, defaulting to quarterly as most bank deposits do
+func CompoundingPeriods(frequency string) int {
+	switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(frequency), "-", "")) {
+	case "monthly":
+		return 12
+	case "halfyearly", "semiannual", "semiannually":
+		return 2
+	case "yearly", "annual", "annually":
+		return 1
+	default:
+		return 4
+	}
+}
+
+// FixedDepositValue returns the value of a deposit at asOf. Interest compounds
+// at the end of each full period and accrues daily, as simple interest,
+// within the current period. annualRate is a percentage.
+func FixedDepositValue(principal, annualRate float64, periodsPerYear int, start, asOf time.Time) float64 {
+	if principal <= 0 || !asOf.After(start) {
+		return principal
+	}
+	if periodsPerYear <= 0 {
+		periodsPerYear = 4
+	}
+
+	rate := annualRate / 100
+	years := yearsBetween(start, asOf)
+	periods := math.Floor(years * float64(periodsPerYear))
+	partialYears := years - periods/float64(periodsPerYear)
+
+	value := principal * math.Pow(1+rate/float64(periodsPerYear), periods)
+	return value * (1 + rate*partialYears)
+}
+
+// RecurringDepositValue returns the value at asOf of a recurring deposit with
+// a monthly installment starting on start, along with the number of
+// installments paid so far. Each installment grows like its own fixed deposit.
+func RecurringDepositValue(installment, annualRate float64, periodsPerYear int, start, asOf time.Time, tenureMonths int) (float64, int) {
+	value, paid := 0.0, 0
+	for k := 0; tenureMonths <= 0 || k < tenureMonths; k++ {
+		due := start.AddDate(0, k, 0)
+		if due.After(asOf) {
+			break
+		}
+		value += FixedDepositValue(installment, annualRate, periodsPerYear, due, asOf)
+		paid++
+	}
+	return value, paid
+}
+
+//
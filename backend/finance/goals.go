@@ -0,0 +1,46 @@
+package finance
+
+import "math"
+
+// MonthlyRate converts an annual return percentage to the equivalent
+// compounded monthly rate as a fraction
+func MonthlyRate(annualReturn float64) float64 {
+	return math.Pow(1+annualReturn/100, 1.0/12) - 1
+}
+
+// RequiredMonthlyContribution returns the amount to invest at the end of each
+// of the next months for current savings to grow to target at annualReturn
+// percent a year. With no months left it is the whole shortfall.
+func RequiredMonthlyContribution(current, target, annualReturn float64, months int) float64 {
+	if months <= 0 {
+		return math.Max(target-current, 0)
+	}
+
+	rate := MonthlyRate(annualReturn)
+	growth := math.Pow(1+rate, float64(months))
+	shortfall := target - current*growth
+	if shortfall <= 0 {
+		return 0
+	}
+	if rate == 0 {
+		return shortfall / float64(months)
+	}
+	return shortfall * rate / (growth - 1)
+}
+
+// MonthsToTarget returns how many months of growth at annualReturn percent,
+// with monthly added at the end of each month, current savings need to reach
+// target. It reports false when target is not reached within maxMonths.
+func MonthsToTarget(current, target, monthly, annualReturn float64, maxMonths int) (int, bool) {
+	rate := MonthlyRate(annualReturn)
+	value := current
+	for months := 0; months <= maxMonths; months++ {
+		if value >= target {
+			return months, true
+		}
+		value = value*(1+rate) + monthly
+	}
+	return 0, false
+}
+
+//
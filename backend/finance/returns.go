@@ -0,0 +1,141 @@
+package finance
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+const daysPerYear = 365.0
+
+// ErrNoSolution is returned when a rate cannot be computed from the given cash flows
+var ErrNoSolution = errors.New("no rate of return could be computed for these cash flows")
+
+// CashFlow is a dated movement of money. Money put in is negative, money
+// taken out (or the closing value of a holding) is positive.
+type CashFlow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// XIRR calculates the annualized money-weighted rate of return of irregular
+// cash flows, returned as a fraction (0.12 for 12%)
+func XIRR(flows []CashFlow) (float64, error) {
+	if len(flows) < 2 {
+		return 0, ErrNoSolution
+	}
+
+	sorted := sortedFlows(flows)
+	hasIn, hasOut := false, false
+	for _, f := range sorted {
+		if f.Amount < 0 {
+			hasIn = true
+		} else if f.Amount > 0 {
+			hasOut = true
+		}
+	}
+	if !hasIn || !hasOut || !sorted[len(sorted)-1].Date.After(sorted[0].Date) {
+		return 0, ErrNoSolution
+	}
+
+	start := sorted[0].Date
+	npv := func(rate float64) (float64, float64) {
+		value, derivative := 0.0, 0.0
+		for _, f := range sorted {
+			years := yearsBetween(start, f.Date)
+			factor := math.Pow(1+rate, years)
+			value += f.Amount / factor
+			derivative -= years * f.Amount / (factor * (1 + rate))
+		}
+		return value, derivative
+	}
+
+	// Newton-Raphson from a 10% guess usually converges in a few steps
+	rate := 0.1
+	for i := 0; i < 100; i++ {
+		value, derivative := npv(rate)
+		if math.Abs(value) < 1e-7 {
+			return rate, nil
+		}
+		if derivative == 0 {
+			break
+		}
+		next := rate - value/derivative
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-rate) < 1e-10 {
+			return next, nil
+		}
+		rate = next
+	}
+
+	// Fall back to bisection, which is slower but always converges once bracketed
+	low, high := -0.9999, 1.0
+	lowValue, _ := npv(low)
+	highValue, _ := npv(high)
+	for lowValue*highValue > 0 {
+		if high > 1e6 {
+			return 0, ErrNoSolution
+		}
+		high *= 2
+		highValue, _ = npv(high)
+	}
+	for i := 0; i < 300; i++ {
+		mid := (low + high) / 2
+		midValue, _ := npv(mid)
+		if math.Abs(midValue) < 1e-7 || (high-low)/2 < 1e-12 {
+			return mid, nil
+		}
+		if midValue*lowValue < 0 {
+			high = mid
+		} else {
+			low, lowValue = mid, midValue
+		}
+	}
+	return (low + high) / 2, nil
+}
+
+// CAGR calculates the compound annual growth rate from a starting and ending
+// value over a number of years, returned as a fraction
+func CAGR(begin, end, years float64) (float64, error) {
+	if begin <= 0 || end < 0 || years <= 0 {
+		return 0, ErrNoSolution
+	}
+	return math.Pow(end/begin, 1/years) - 1, nil
+}
+
+// WeightedHoldingYears returns the average time the money put in (negative
+// flows) has been held up to asOf, weighted by amount
+func WeightedHoldingYears(flows []CashFlow, asOf time.Time) float64 {
+	weighted, total := 0.0, 0.0
+	for _, f := range flows {
+		if f.Amount >= 0 || f.Date.After(asOf) {
+			continue
+		}
+		weighted += -f.Amount * yearsBetween(f.Date, asOf)
+		total += -f.Amount
+	}
+	if total == 0 {
+		return 0
+	}
+	return weighted / total
+}
+
+// yearsBetween returns the length of time between two dates in years
+func yearsBetween(from, to time.Time) float64 {
+	return to.Sub(from).Hours() / 24 / daysPerYear
+}
+
+// sortedFlows returns a copy of the cash flows ordered by date
+func sortedFlows(flows []CashFlow) []CashFlow {
+	sorted := make([]CashFlow, len(flows))
+	copy(sorted, flows)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].Date.Before(sorted[b].Date)
+	})
+	return sorted
+}
+
+//
@@ -0,0 +1,157 @@
+package finance
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestXIRR(t *testing.T) {
+	tests := []struct {
+		name  string
+		flows []CashFlow
+		want  float64
+	}{
+		{
+			name: "one year doubling",
+			flows: []CashFlow{
+				{Date: date(2021, 1, 1), Amount: -1000},
+				{Date: date(2022, 1, 1), Amount: 2000},
+			},
+			want: 1,
+		},
+		{
+			name: "ten percent over a leap year",
+			flows: []CashFlow{
+				{Date: date(2020, 1, 1), Amount: -1000},
+				{Date: date(2021, 1, 1), Amount: 1100},
+			},
+			want: math.Pow(1.1, daysPerYear/366) - 1,
+		},
+		{
+			name: "irregular flows (spreadsheet XIRR example)",
+			flows: []CashFlow{
+				{Date: date(2008, 1, 1), Amount: -10000},
+				{Date: date(2008, 3, 1), Amount: 2750},
+				{Date: date(2008, 10, 30), Amount: 4250},
+				{Date: date(2009, 2, 15), Amount: 3250},
+				{Date: date(2009, 4, 1), Amount: 2750},
+			},
+			want: 0.373362535,
+		},
+		{
+			name: "unsorted flows",
+			flows: []CashFlow{
+				{Date: date(2022, 1, 1), Amount: 2000},
+				{Date: date(2021, 1, 1), Amount: -1000},
+			},
+			want: 1,
+		},
+		{
+			// Newton's first step from 10% lands below -100%, so the rate is
+			// found by bisection
+			name: "near total loss needs bisection",
+			flows: []CashFlow{
+				{Date: date(2021, 1, 1), Amount: -1000},
+				{Date: date(2022, 1, 1), Amount: 1},
+			},
+			want: -0.999,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := XIRR(tt.flows)
+			if err != nil {
+				t.Fatalf("XIRR() error = %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("XIRR() = %.9f, want %.9f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXIRRNoSolution(t *testing.T) {
+	tests := []struct {
+		name  string
+		flows []CashFlow
+	}{
+		{name: "no flows"},
+		{
+			name:  "single flow",
+			flows: []CashFlow{{Date: date(2021, 1, 1), Amount: -1000}},
+		},
+		{
+			name: "only money put in",
+			flows: []CashFlow{
+				{Date: date(2021, 1, 1), Amount: -1000},
+				{Date: date(2022, 1, 1), Amount: -500},
+			},
+		},
+		{
+			name: "only money taken out",
+			flows: []CashFlow{
+				{Date: date(2021, 1, 1), Amount: 1000},
+				{Date: date(2022, 1, 1), Amount: 500},
+			},
+		},
+		{
+			name: "all on one day",
+			flows: []CashFlow{
+				{Date: date(2021, 1, 1), Amount: -1000},
+				{Date: date(2021, 1, 1), Amount: 1100},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := XIRR(tt.flows); !errors.Is(err, ErrNoSolution) {
+				t.Errorf("XIRR() error = %v, want ErrNoSolution", err)
+			}
+		})
+	}
+}
+
+func TestCAGR(t *testing.T) {
+	tests := []struct {
+		name              string
+		begin, end, years float64
+		want              float64
+		wantErr           bool
+	}{
+		{name: "ten percent for two years", begin: 100, end: 121, years: 2, want: 0.1},
+		{name: "half a year", begin: 100, end: 110, years: 0.5, want: 0.21},
+		{name: "total loss", begin: 100, end: 0, years: 3, want: -1},
+		{name: "zero years", begin: 100, end: 110, years: 0, wantErr: true},
+		{name: "negative years", begin: 100, end: 110, years: -1, wantErr: true},
+		{name: "nothing invested", begin: 0, end: 110, years: 1, wantErr: true},
+		{name: "negative end value", begin: 100, end: -10, years: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CAGR(tt.begin, tt.end, tt.years)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoSolution) {
+					t.Errorf("CAGR() error = %v, want ErrNoSolution", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CAGR() error = %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CAGR() = %.9f, want %.9f", got, tt.want)
+			}
+		})
+	}
+}
+
+//
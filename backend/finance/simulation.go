@@ -0,0 +1,126 @@
+package finance
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// maxSimulationBands caps how many points in time a simulation reports
+const maxSimulationBands = 60
+
+// SimulatedAsset is a holding whose value follows a random walk. Monthly
+// returns are lognormal with the given annual mean and volatility.
+type SimulatedAsset struct {
+	Value      float64 // Starting value
+	Monthly    float64 // Added at the end of each month
+	Return     float64 // Expected annual return, %
+	Volatility float64 // Annual volatility of returns, %
+	Group      string  // Assets in the same group (asset class) move together
+}
+
+// SimulationBand holds percentiles of the simulated total value after a number of months
+type SimulationBand struct {
+	Month                   int
+	P10, P25, P50, P75, P90 float64
+}
+
+// SimulationResult is the outcome of a Monte Carlo simulation
+type SimulationResult struct {
+	Probability float64 // Share of paths ending at or above the target, %
+	Bands       []SimulationBand
+}
+
+// MonteCarlo simulates paths of the assets' total value over months and
+// reports how often it reaches target. The same seed gives the same result.
+func MonteCarlo(assets []SimulatedAsset, target float64, months, paths int, seed int64) SimulationResult {
+	if paths <= 0 {
+		return SimulationResult{}
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	// One random shock per group per month, so that assets of a class move together
+	groupIndex := make(map[string]int)
+	groups := make([]int, len(assets))
+	drift := make([]float64, len(assets))
+	spread := make([]float64, len(assets))
+	for i, a := range assets {
+		index, ok := groupIndex[a.Group]
+		if !ok {
+			index = len(groupIndex)
+			groupIndex[a.Group] = index
+		}
+		groups[i] = index
+
+		sigma := a.Volatility / 100
+		growth := math.Max(1+a.Return/100, 0.01)
+		drift[i] = (math.Log(growth) - sigma*sigma/2) / 12
+		spread[i] = sigma / math.Sqrt(12)
+	}
+
+	values := make([][]float64, paths)
+	for p := range values {
+		values[p] = make([]float64, len(assets))
+		for i, a := range assets {
+			values[p][i] = a.Value
+		}
+	}
+
+	step := (months + maxSimulationBands - 1) / maxSimulationBands
+	if step < 1 {
+		step = 1
+	}
+
+	totals := make([]float64, paths)
+	band := func(month int) SimulationBand {
+		for p := range values {
+			totals[p] = 0
+			for _, v := range values[p] {
+				totals[p] += v
+			}
+		}
+		sort.Float64s(totals)
+		return SimulationBand{
+			Month: month,
+			P10:   percentile(totals, 10),
+			P25:   percentile(totals, 25),
+			P50:   percentile(totals, 50),
+			P75:   percentile(totals, 75),
+			P90:   percentile(totals, 90),
+		}
+	}
+
+	result := SimulationResult{Bands: []SimulationBand{band(0)}}
+	shocks := make([]float64, len(groupIndex))
+	for month := 1; month <= months; month++ {
+		for p := range values {
+			for g := range shocks {
+				shocks[g] = rng.NormFloat64()
+			}
+			for i, a := range assets {
+				values[p][i] = values[p][i]*math.Exp(drift[i]+spread[i]*shocks[groups[i]]) + a.Monthly
+			}
+		}
+		if month%step == 0 || month == months {
+			result.Bands = append(result.Bands, band(month))
+		}
+	}
+
+	// totals holds the sorted final values after the last band
+	reached := len(totals) - sort.SearchFloat64s(totals, target)
+	result.Probability = float64(reached) / float64(paths) * 100
+	return result
+}
+
+// percentile returns the pth percentile of sorted values, interpolating between neighbours
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+//
@@ -0,0 +1,84 @@
+package finance
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+var simulationAssets = []SimulatedAsset{
+	{Value: 100000, Monthly: 5000, Return: 12, Volatility: 18, Group: "Equity"},
+	{Value: 50000, Monthly: 2000, Return: 7, Volatility: 4, Group: "Debt"},
+	{Value: 20000, Return: 12, Volatility: 18, Group: "Equity"},
+}
+
+func TestMonteCarloSameSeed(t *testing.T) {
+	first := MonteCarlo(simulationAssets, 600000, 60, 500, 42)
+	second := MonteCarlo(simulationAssets, 600000, 60, 500, 42)
+
+	if first.Probability != second.Probability {
+		t.Errorf("Probability = %v then %v with the same seed", first.Probability, second.Probability)
+	}
+	if !reflect.DeepEqual(first.Bands, second.Bands) {
+		t.Error("Bands differ with the same seed")
+	}
+
+	other := MonteCarlo(simulationAssets, 600000, 60, 500, 43)
+	if reflect.DeepEqual(first.Bands, other.Bands) {
+		t.Error("Bands are identical for different seeds")
+	}
+}
+
+func TestMonteCarloZeroVolatility(t *testing.T) {
+	assets := []SimulatedAsset{{Value: 100000, Monthly: 1000, Return: 6, Group: "Cash"}}
+	months := 24
+
+	// Without volatility every path grows the same way
+	growth := math.Pow(1.06, 1.0/12)
+	want := 100000.0
+	for m := 0; m < months; m++ {
+		want = want*growth + 1000
+	}
+
+	tests := []struct {
+		name   string
+		target float64
+		want   float64
+	}{
+		{name: "target below the outcome", target: want * 0.99, want: 100},
+		{name: "target above the outcome", target: want * 1.01, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MonteCarlo(assets, tt.target, months, 200, 7)
+			if result.Probability != tt.want {
+				t.Errorf("Probability = %v, want %v", result.Probability, tt.want)
+			}
+			last := result.Bands[len(result.Bands)-1]
+			if math.Abs(last.P10-want) > 1e-6 || math.Abs(last.P90-want) > 1e-6 {
+				t.Errorf("final band P10 = %v, P90 = %v, want both %v", last.P10, last.P90, want)
+			}
+		})
+	}
+}
+
+func TestMonteCarloPercentilesMonotonic(t *testing.T) {
+	result := MonteCarlo(simulationAssets, 600000, 120, 1000, 1)
+	if len(result.Bands) == 0 {
+		t.Fatal("no bands")
+	}
+	if len(result.Bands) > maxSimulationBands+1 {
+		t.Errorf("%d bands, want at most %d", len(result.Bands), maxSimulationBands+1)
+	}
+
+	for _, b := range result.Bands {
+		if !(b.P10 <= b.P25 && b.P25 <= b.P50 && b.P50 <= b.P75 && b.P75 <= b.P90) {
+			t.Errorf("month %d: percentiles out of order: %+v", b.Month, b)
+		}
+	}
+	if result.Probability < 0 || result.Probability > 100 {
+		t.Errorf("Probability = %v, want between 0 and 100", result.Probability)
+	}
+}
+
+//
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"investment-tracker-backend/services"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StartDepositAccrual accrues interest on fixed and recurring deposits
+func StartDepositAccrual(db *gorm.DB, interval time.Duration) (stop func()) {
+	return Every("deposit-accrual", interval, func() error {
+		count, err := services.AccrueDeposits(db, time.Now())
+		if err != nil {
+			return err
+		}
+		log.Printf("Accrued interest on %d deposits", count)
+		return nil
+	})
+}
+
+//
@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"investment-tracker-backend/pricing"
+	"investment-tracker-backend/services"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StartRevaluation periodically revalues holdings from the price provider
+func StartRevaluation(db *gorm.DB, provider pricing.PriceProvider, interval time.Duration) (stop func()) {
+	return Every("revaluation", interval, func() error {
+		summary, err := services.RevalueInvestments(db, provider, time.Now())
+		if err != nil {
+			return err
+		}
+		log.Printf("Revalued %d of %d holdings, refreshed %d goals, %d symbols without a price",
+			summary.Revalued, summary.Checked, summary.Goals, len(summary.Missing))
+		return nil
+	})
+}
+
+//
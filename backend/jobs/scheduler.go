@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+// Every runs task immediately and then once per interval in the background.
+// Calling the returned function stops the schedule.
+func Every(name string, interval time.Duration, task func() error) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	run := func() {
+		start := time.Now()
+		if err := task(); err != nil {
+			log.Printf("❌ Job %s failed: %v", name, err)
+			return
+		}
+		log.Printf("✅ Job %s finished in %s", name, time.Since(start).Round(time.Millisecond))
+	}
+
+	go func() {
+		run()
+		for {
+			select {
+			case <-ticker.C:
+				run()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+//
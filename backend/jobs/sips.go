@@ -0,0 +1,24 @@
+package jobs
+
+import (
+	"investment-tracker-backend/pricing"
+	"investment-tracker-backend/services"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StartSIPs posts SIP installments as they fall due
+func StartSIPs(db *gorm.DB, provider pricing.PriceProvider, interval time.Duration) (stop func()) {
+	return Every("sip-installments", interval, func() error {
+		summary, err := services.PostDueSIPInstallments(db, provider, time.Now())
+		if err != nil {
+			return err
+		}
+		log.Printf("Posted %d SIP installments across %d SIPs", summary.InstallmentsPosted, summary.SIPsChecked)
+		return nil
+	})
+}
+
+//
@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"investment-tracker-backend/pricing"
+	"investment-tracker-backend/services"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StartSnapshots records every user's portfolio snapshot for the current day.
+// Re-running on the same day overwrites that day's snapshot.
+func StartSnapshots(db *gorm.DB, provider pricing.PriceProvider, interval time.Duration) (stop func()) {
+	return Every("portfolio-snapshots", interval, func() error {
+		count, err := services.SnapshotAllUsers(db, time.Now(), provider)
+		if err != nil {
+			return err
+		}
+		log.Printf("Recorded portfolio snapshots for %d users", count)
+		return nil
+	})
+}
+
+//
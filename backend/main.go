@@ -3,9 +3,14 @@ package main
 import (
 	"investment-tracker-backend/config"
 	"investment-tracker-backend/controllers"
+	"investment-tracker-backend/jobs"
+	"investment-tracker-backend/notify"
+	"investment-tracker-backend/pricing"
 	"investment-tracker-backend/routes"
+	"investment-tracker-backend/services"
 	"log"
 	"os"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -24,6 +29,52 @@ func main() {
 	// Initialize OAuth configuration
 	controllers.InitOAuth()
 
+	// Revalue holdings from a local price file when one is configured
+	var provider pricing.PriceProvider
+	if priceFile := os.Getenv("PRICE_FILE"); priceFile != "" {
+		fileProvider, err := pricing.NewFileProvider(priceFile)
+		if err != nil {
+			log.Fatal("Failed to load price file:", err)
+		}
+		provider = fileProvider
+
+		interval := 24 * time.Hour
+		if value := os.Getenv("REVALUATION_INTERVAL"); value != "" {
+			if interval, err = time.ParseDuration(value); err != nil {
+				log.Fatal("Invalid REVALUATION_INTERVAL:", err)
+			}
+		}
+
+		jobs.StartRevaluation(config.DB, provider, interval)
+		log.Printf("✅ Price revaluation scheduled every %s from %s", interval, priceFile)
+	}
+	controllers.InitPriceProvider(provider)
+
+	// Load benchmark price series from a local directory of CSV files
+	if benchmarkDir := os.Getenv("BENCHMARK_DIR"); benchmarkDir != "" {
+		count, err := services.LoadBenchmarkDir(config.DB, benchmarkDir)
+		if err != nil {
+			log.Fatal("Failed to load benchmarks:", err)
+		}
+		log.Printf("✅ Loaded %d benchmark(s) from %s", count, benchmarkDir)
+	}
+
+	// Accrue interest on fixed and recurring deposits daily
+	jobs.StartDepositAccrual(config.DB, 24*time.Hour)
+
+	// Post SIP installments as they fall due
+	jobs.StartSIPs(config.DB, provider, 24*time.Hour)
+
+	// Record daily portfolio snapshots for net-worth history
+	jobs.StartSnapshots(config.DB, provider, 24*time.Hour)
+
+	// Goal milestones and status changes go to the log, and to a webhook when configured
+	notify.Register(notify.LogNotifier{})
+	if webhookURL := os.Getenv("GOAL_WEBHOOK_URL"); webhookURL != "" {
+		notify.Register(notify.NewWebhookNotifier(webhookURL))
+		log.Println("✅ Goal events will be posted to the webhook")
+	}
+
 	// Create Gin router
 	router := gin.Default()
 	router.SetTrustedProxies(nil)
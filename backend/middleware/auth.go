@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -60,4 +62,26 @@ func GetUserIDFromContext(c *gin.Context) (string, bool) {
 	return userID.(string), true
 }
 
+// AdminMiddleware only lets through users whose email is listed in the
+// comma-separated ADMIN_EMAILS environment variable. Must run after AuthMiddleware.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		email, exists := GetEmailFromContext(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+
+		for _, admin := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+			if admin = strings.TrimSpace(admin); admin != "" && strings.EqualFold(admin, email) {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		c.Abort()
+	}
+}
 
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AllocationTarget is the share of the portfolio a user wants in an asset class
+type AllocationTarget struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	UserID     uint    `gorm:"not null;index" json:"user_id,omitempty"`
+	User       User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	AssetClass string  `gorm:"type:varchar(50);not null" json:"asset_class"` // Equity, Debt, Gold, Real Estate, Cash, Other
+	Percent    float64 `gorm:"type:decimal(5,2);not null" json:"percent"`
+}
+
+// IsAssetClass reports whether name is a known asset class
+func IsAssetClass(name string) bool {
+	for _, class := range AssetClasses {
+		if class == name {
+			return true
+		}
+	}
+	return false
+}
+
+//
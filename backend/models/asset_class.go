@@ -0,0 +1,77 @@
+package models
+
+import "strings"
+
+// Asset classes investments are grouped into
+const (
+	AssetClassEquity     = "Equity"
+	AssetClassDebt       = "Debt"
+	AssetClassGold       = "Gold"
+	AssetClassRealEstate = "Real Estate"
+	AssetClassCash       = "Cash"
+	AssetClassOther      = "Other"
+)
+
+// AssetClasses lists every asset class
+var AssetClasses = []string{AssetClassEquity, AssetClassDebt, AssetClassGold, AssetClassRealEstate, AssetClassCash, AssetClassOther}
+
+// debtFundKeywords identify debt mutual funds by scheme name
+var debtFundKeywords = []string{"debt", "liquid", "gilt", "bond", "money market", "overnight", "banking and psu", "banking & psu", "duration", "credit risk", "floater"}
+
+// AssetClassForType maps the free-text investment type to an asset class
+func AssetClassForType(investmentType string) string {
+	t := strings.ToLower(strings.TrimSpace(investmentType))
+	switch {
+	case hasWord(t, "sgb") || containsAny(t, "gold", "silver"):
+		return AssetClassGold
+	case hasWord(t, "reit", "land") || containsAny(t, "real estate", "property"):
+		return AssetClassRealEstate
+	case hasWord(t, "fd", "rd", "ppf", "epf", "vpf", "nsc", "scss") ||
+		containsAny(t, "fixed deposit", "recurring deposit", "bond", "debenture", "debt", "gilt", "sukanya", "post office"):
+		return AssetClassDebt
+	case containsAny(t, "savings", "cash", "liquid"):
+		return AssetClassCash
+	case hasWord(t, "etf", "nps") || containsAny(t, "stock", "share", "equity", "mutual fund", "index"):
+		return AssetClassEquity
+	default:
+		return AssetClassOther
+	}
+}
+
+// AssetClass returns the investment's asset class. Mutual funds default to
+// equity unless the scheme name marks them as a debt fund.
+func (i *Investment) AssetClass() string {
+	class := AssetClassForType(i.Type)
+	if class == AssetClassEquity && strings.Contains(strings.ToLower(i.Type), "mutual fund") &&
+		containsAny(strings.ToLower(i.Name), debtFundKeywords...) {
+		return AssetClassDebt
+	}
+	return class
+}
+
+// hasWord reports whether s contains any of the words as a whole word
+func hasWord(s string, words ...string) bool {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	for _, field := range fields {
+		for _, word := range words {
+			if field == word {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+//
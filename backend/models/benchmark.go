@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Benchmark is a market index, such as the Nifty 50, that holdings are compared against
+type Benchmark struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Name        string `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"` // e.g. NIFTY50
+	Description string `gorm:"type:text" json:"description"`
+}
+
+// BenchmarkPrice is a benchmark's closing level on a day
+type BenchmarkPrice struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	BenchmarkID uint       `gorm:"not null;uniqueIndex:idx_benchmark_price_date" json:"benchmark_id"`
+	Benchmark   *Benchmark `gorm:"foreignKey:BenchmarkID;constraint:OnDelete:CASCADE" json:"-"`
+	Date        time.Time  `gorm:"type:date;not null;uniqueIndex:idx_benchmark_price_date" json:"date"`
+	Close       float64    `gorm:"type:decimal(15,4);not null" json:"close"`
+}
+
+//
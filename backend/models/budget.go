@@ -12,13 +12,17 @@ type Budget struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
-	UserID        uint    `gorm:"not null;index" json:"user_id"`
-	User          User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
-	Month         string  `gorm:"type:varchar(7);not null;index" json:"month"` // Format: "2024-01"
-	Income        float64 `gorm:"type:decimal(15,2);default:0" json:"income"`
-	TotalExpenses float64 `gorm:"type:decimal(15,2);default:0" json:"total_expenses"`
-	Savings       float64 `gorm:"type:decimal(15,2);default:0" json:"savings"`
-	SavingsGoal   float64 `gorm:"type:decimal(15,2);default:0" json:"savings_goal"`
+	UserID        uint   `gorm:"not null;index" json:"user_id"`
+	User          User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Month         string `gorm:"type:varchar(7);not null;index" json:"month"` // Format: "2024-01"
+	Income        Money  `gorm:"type:decimal(15,2);default:0" json:"income"`
+	TotalExpenses Money  `gorm:"type:decimal(15,2);default:0" json:"total_expenses"`
+	Savings       Money  `gorm:"type:decimal(15,2);default:0" json:"savings"`
+	SavingsGoal   Money  `gorm:"type:decimal(15,2);default:0" json:"savings_goal"`
+	Currency      string `gorm:"type:varchar(3);default:'INR'" json:"currency"`
+
+	Categories     []BudgetCategory `gorm:"foreignKey:BudgetID" json:"categories,omitempty"` // Per-category limits, saved through SaveBudgetCategories
+	MissingFXRates []string         `gorm:"-" json:"missing_fx_rates,omitempty"`             // Currency pairs of expenses left out of Spent for lack of a rate
 }
 
 // CalculateSavings calculates savings from income and expenses
@@ -29,7 +33,7 @@ func (b *Budget) CalculateSavings() {
 // CalculateSavingsPercentage calculates the savings percentage
 func (b *Budget) CalculateSavingsPercentage() float64 {
 	if b.SavingsGoal > 0 {
-		return (b.Savings / b.SavingsGoal) * 100
+		return b.Savings.Ratio(b.SavingsGoal) * 100
 	}
 	return 0
 }
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// BudgetCategory is a budget's spending limit for one expense category. What
+// was spent comes from the month's expenses of that category.
+type BudgetCategory struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID   uint    `gorm:"not null;index" json:"user_id,omitempty"`
+	User     User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	BudgetID uint    `gorm:"not null;uniqueIndex:idx_budget_category" json:"budget_id"`
+	Budget   *Budget `gorm:"foreignKey:BudgetID;constraint:OnDelete:CASCADE" json:"-"`
+	Category string  `gorm:"type:varchar(100);not null;uniqueIndex:idx_budget_category" json:"category"` // Matches Expense.Category
+	Limit    Money   `gorm:"column:limit_amount;type:decimal(15,2);not null" json:"limit"`
+
+	Spent     Money `gorm:"-" json:"spent"` // In the budget's currency
+	Remaining Money `gorm:"-" json:"remaining"`
+	OverLimit bool  `gorm:"-" json:"over_limit"`
+}
+
+// CalculateRemaining fills in what is left of the limit and whether it was exceeded
+func (c *BudgetCategory) CalculateRemaining() {
+	c.Remaining = c.Limit - c.Spent
+	c.OverLimit = c.Spent > c.Limit
+}
+
+// ValidateBudgetCategories checks that every category is named once and has a limit
+func ValidateBudgetCategories(categories []BudgetCategory) error {
+	seen := make(map[string]bool)
+	for i := range categories {
+		categories[i].Category = strings.TrimSpace(categories[i].Category)
+		name := strings.ToLower(categories[i].Category)
+		if name == "" {
+			return errors.New("Budget category name is required")
+		}
+		if categories[i].Limit <= 0 {
+			return errors.New("Limit for " + categories[i].Category + " must be greater than 0")
+		}
+		if seen[name] {
+			return errors.New("Budget category " + categories[i].Category + " is listed more than once")
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// MonthRange returns the first day of the budget's month and the first day of the next
+func (b *Budget) MonthRange() (time.Time, time.Time, error) {
+	from, err := time.Parse("2006-01", b.Month)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("Budget month must be formatted as YYYY-MM")
+	}
+	return from, from.AddDate(0, 1, 0), nil
+}
+
+//
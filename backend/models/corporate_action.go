@@ -0,0 +1,109 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Corporate action types and statuses
+const (
+	CorporateActionSplit        = "Split"
+	CorporateActionBonus        = "Bonus"
+	CorporateActionMerger       = "Merger"
+	CorporateActionSymbolChange = "Symbol Change"
+
+	CorporateActionPending  = "Pending"
+	CorporateActionApplied  = "Applied"
+	CorporateActionReversed = "Reversed"
+)
+
+// CorporateAction is a split, bonus issue, merger or symbol change that
+// adjusts every holding of a symbol
+type CorporateAction struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Symbol     string     `gorm:"type:varchar(50);not null;index" json:"symbol"`
+	Type       string     `gorm:"type:varchar(20);not null" json:"type"`          // Split, Bonus, Merger, Symbol Change
+	RatioFrom  float64    `gorm:"type:decimal(10,4);default:1" json:"ratio_from"` // Split 1:5 is from 1 to 5; bonus 1:2 is 1 new share for every 2 held
+	RatioTo    float64    `gorm:"type:decimal(10,4);default:1" json:"ratio_to"`
+	NewSymbol  string     `gorm:"type:varchar(50)" json:"new_symbol,omitempty"` // For mergers and symbol changes
+	ExDate     time.Time  `gorm:"not null" json:"ex_date"`
+	Status     string     `gorm:"type:varchar(20);default:'Pending'" json:"status"` // Pending, Applied, Reversed
+	AppliedAt  *time.Time `json:"applied_at,omitempty"`
+	ReversedAt *time.Time `json:"reversed_at,omitempty"`
+	Notes      string     `gorm:"type:text" json:"notes"`
+}
+
+// CorporateActionAudit records how one investment changed when an action was
+// applied or reversed
+type CorporateActionAudit struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	CorporateActionID uint    `gorm:"not null;index" json:"corporate_action_id"`
+	InvestmentID      uint    `gorm:"not null;index" json:"investment_id"`
+	UserID            uint    `gorm:"not null;index" json:"user_id"`
+	Operation         string  `gorm:"type:varchar(20);not null" json:"operation"` // Apply, Reverse
+	UnitsBefore       float64 `gorm:"type:decimal(20,6)" json:"units_before"`
+	UnitsAfter        float64 `gorm:"type:decimal(20,6)" json:"units_after"`
+	SymbolBefore      string  `gorm:"type:varchar(50)" json:"symbol_before"`
+	SymbolAfter       string  `gorm:"type:varchar(50)" json:"symbol_after"`
+	InvestedBefore    Money   `gorm:"type:decimal(15,2)" json:"invested_before"`
+	InvestedAfter     Money   `gorm:"type:decimal(15,2)" json:"invested_after"`
+	TransactionID     *uint   `json:"transaction_id,omitempty"` // Split transaction added to the ledger
+}
+
+// Validate checks the action's type and ratio
+func (a *CorporateAction) Validate() error {
+	a.Symbol = strings.TrimSpace(a.Symbol)
+	a.NewSymbol = strings.TrimSpace(a.NewSymbol)
+	if a.Symbol == "" {
+		return errors.New("Symbol is required")
+	}
+
+	switch a.Type {
+	case CorporateActionSplit, CorporateActionBonus:
+		if a.RatioFrom <= 0 || a.RatioTo <= 0 {
+			return errors.New("Ratio from and ratio to must be greater than 0")
+		}
+	case CorporateActionMerger:
+		if a.RatioFrom <= 0 || a.RatioTo <= 0 {
+			return errors.New("Ratio from and ratio to must be greater than 0")
+		}
+		if a.NewSymbol == "" {
+			return errors.New("New symbol is required for a merger")
+		}
+	case CorporateActionSymbolChange:
+		if a.NewSymbol == "" {
+			return errors.New("New symbol is required for a symbol change")
+		}
+		a.RatioFrom, a.RatioTo = 1, 1
+	default:
+		return errors.New("Type must be one of Split, Bonus, Merger, Symbol Change")
+	}
+
+	if a.ExDate.IsZero() {
+		a.ExDate = time.Now()
+	}
+	a.Status = CorporateActionPending
+	return nil
+}
+
+// Multiplier returns how many units are held after the action for each unit held before
+func (a *CorporateAction) Multiplier() float64 {
+	if a.RatioFrom <= 0 {
+		return 1
+	}
+	if a.Type == CorporateActionBonus {
+		return (a.RatioFrom + a.RatioTo) / a.RatioFrom
+	}
+	return a.RatioTo / a.RatioFrom
+}
+
+//
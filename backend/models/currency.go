@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultCurrency applies to amounts recorded without a currency
+const DefaultCurrency = "INR"
+
+// FXRate is the value of one unit of FromCurrency in ToCurrency on a day
+type FXRate struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	FromCurrency string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_fx_rate_pair_date" json:"from_currency"`
+	ToCurrency   string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_fx_rate_pair_date" json:"to_currency"`
+	Date         time.Time `gorm:"type:date;not null;uniqueIndex:idx_fx_rate_pair_date" json:"date"`
+	Rate         float64   `gorm:"type:decimal(20,8);not null" json:"rate"`
+}
+
+// NormalizeCurrency upper-cases an ISO 4217 code, defaulting to DefaultCurrency
+func NormalizeCurrency(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency
+	}
+	return code
+}
+
+// ValidCurrency reports whether code looks like an ISO 4217 code (three letters)
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+//
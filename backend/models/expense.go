@@ -17,7 +17,8 @@ type Expense struct {
 	BudgetID    *uint     `gorm:"index" json:"budget_id,omitempty"`
 	Budget      *Budget   `gorm:"foreignKey:BudgetID;constraint:OnDelete:SET NULL" json:"-"`
 	Category    string    `gorm:"type:varchar(100);not null" json:"category" binding:"required"` // Food, Transport, Entertainment, etc.
-	Amount      float64   `gorm:"type:decimal(15,2);not null" json:"amount" binding:"required"`
+	Amount      Money     `gorm:"type:decimal(15,2);not null" json:"amount" binding:"required"`
+	Currency    string    `gorm:"type:varchar(3);default:'INR'" json:"currency"`
 	Description string    `gorm:"type:text" json:"description"`
 	Date        time.Time `gorm:"not null" json:"date"`
 }
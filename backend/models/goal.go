@@ -1,11 +1,33 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// Goal categories with a default inflation rate
+const (
+	GoalCategoryEducation  = "Education"
+	GoalCategoryHealthcare = "Healthcare"
+	GoalCategoryRetirement = "Retirement"
+	GoalCategoryHouse      = "House"
+	GoalCategoryVehicle    = "Vehicle"
+	GoalCategoryTravel     = "Travel"
+)
+
+// CategoryInflation is the annual inflation, in percent, assumed for a goal
+// category when the goal has no rate of its own. Other categories assume none.
+var CategoryInflation = map[string]float64{
+	GoalCategoryEducation:  10,
+	GoalCategoryHealthcare: 12,
+	GoalCategoryRetirement: 6,
+	GoalCategoryHouse:      7,
+	GoalCategoryVehicle:    5,
+	GoalCategoryTravel:     6,
+}
+
 type Goal struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
 	CreatedAt time.Time      `json:"created_at"`
@@ -15,25 +37,73 @@ type Goal struct {
 	UserID        uint      `gorm:"not null;index" json:"user_id,omitempty"`
 	User          User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
 	Name          string    `gorm:"type:varchar(255);not null" json:"name"`
-	TargetAmount  float64   `gorm:"type:decimal(15,2);not null" json:"target_amount"`
-	CurrentAmount float64   `gorm:"type:decimal(15,2);default:0" json:"current_amount"`
+	TargetAmount  Money     `gorm:"type:decimal(15,2);not null" json:"target_amount"`
+	CurrentAmount Money     `gorm:"type:decimal(15,2);default:0" json:"current_amount"`
+	Currency      string    `gorm:"type:varchar(3);default:'INR'" json:"currency"`
 	Deadline      time.Time `json:"deadline,omitempty"`
 	Status        string    `gorm:"type:varchar(50);default:'Planned'" json:"status"`  // Planned, In Progress, Completed
 	Priority      string    `gorm:"type:varchar(50);default:'Medium'" json:"priority"` // High, Medium, Low
 	Description   string    `gorm:"type:text" json:"description"`
+
+	MonthlyContribution Money               `gorm:"type:decimal(15,2);default:0" json:"monthly_contribution"` // Saved towards the goal each month besides SIPs
+	Category            string              `gorm:"type:varchar(50)" json:"category"`                         // Education, Healthcare, Retirement, House, Vehicle, Travel, Other
+	InflationRate       *float64            `gorm:"type:decimal(5,2)" json:"inflation_rate,omitempty"`        // Annual %, defaults by category
+	Template            string              `gorm:"type:varchar(30)" json:"template,omitempty"`               // Emergency Fund, Retirement, Education
+	TemplateInputs      *GoalTemplateInputs `gorm:"type:jsonb" json:"template_inputs,omitempty"`
+
+	InflatedTargetAmount Money           `gorm:"-" json:"inflated_target_amount"` // Target in money of the deadline
+	RealProgress         float64         `gorm:"-" json:"real_progress"`          // Progress towards the inflated target
+	Projection           *GoalProjection `gorm:"-" json:"projection,omitempty"`
 }
 
 // CalculateProgress calculates the progress percentage
 func (g *Goal) CalculateProgress() float64 {
 	if g.TargetAmount > 0 {
-		return (g.CurrentAmount / g.TargetAmount) * 100
+		return g.CurrentAmount.Ratio(g.TargetAmount) * 100
 	}
 	return 0
 }
 
-// UpdateStatus updates the status based on progress
+// EffectiveInflationRate returns the goal's inflation rate, or its category's default
+func (g *Goal) EffectiveInflationRate() float64 {
+	if g.InflationRate != nil {
+		return *g.InflationRate
+	}
+	return CategoryInflation[g.Category]
+}
+
+// InflatedTarget returns the target amount, entered in money of the day the goal
+// was created, grown by inflation until the deadline
+func (g *Goal) InflatedTarget() Money {
+	rate := g.EffectiveInflationRate()
+	if rate == 0 || g.Deadline.IsZero() {
+		return g.TargetAmount
+	}
+
+	base := g.CreatedAt
+	if base.IsZero() {
+		base = time.Now()
+	}
+	years := g.Deadline.Sub(base).Hours() / 24 / 365
+	if years <= 0 {
+		return g.TargetAmount
+	}
+	return g.TargetAmount.Mul(math.Pow(1+rate/100, years))
+}
+
+// CalculateInflation fills in the inflated target and the progress towards it
+func (g *Goal) CalculateInflation() {
+	g.InflatedTargetAmount = g.InflatedTarget()
+	g.RealProgress = 0
+	if g.InflatedTargetAmount > 0 {
+		g.RealProgress = Round2(g.CurrentAmount.Ratio(g.InflatedTargetAmount) * 100)
+	}
+}
+
+// UpdateStatus updates the status based on progress towards the inflated target
 func (g *Goal) UpdateStatus() {
-	progress := g.CalculateProgress()
+	g.CalculateInflation()
+	progress := g.RealProgress
 	if progress >= 100 {
 		g.Status = "Completed"
 	} else if progress > 0 {
@@ -43,12 +113,17 @@ func (g *Goal) UpdateStatus() {
 	}
 }
 
-// CalculateLinkedInvestmentsTotal calculates total current value of linked investments
-func (g *Goal) CalculateLinkedInvestmentsTotal(investments []Investment) float64 {
-	total := 0.0
+// CalculateLinkedInvestmentsTotal calculates the goal's share of the current value of allocated investments
+func (g *Goal) CalculateLinkedInvestmentsTotal(investments []Investment, allocations []InvestmentGoalAllocation) Money {
+	values := make(map[uint]Money, len(investments))
 	for _, inv := range investments {
-		if inv.GoalID != nil && *inv.GoalID == g.ID {
-			total += inv.CurrentValue
+		values[inv.ID] = inv.CurrentValue
+	}
+
+	var total Money
+	for _, a := range allocations {
+		if a.GoalID == g.ID {
+			total += a.Share(values[a.InvestmentID])
 		}
 	}
 	return total
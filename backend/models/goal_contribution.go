@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Goal contribution types
+const (
+	GoalContributionDeposit    = "Deposit"
+	GoalContributionWithdrawal = "Withdrawal"
+)
+
+// GoalContribution is cash put towards a goal, or taken out of it, outside of
+// any investment, such as money set aside in a savings account
+type GoalContribution struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	UserID   uint      `gorm:"not null;index" json:"user_id,omitempty"`
+	User     User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	GoalID   uint      `gorm:"not null;index" json:"goal_id"`
+	Goal     *Goal     `gorm:"foreignKey:GoalID;constraint:OnDelete:CASCADE" json:"-"`
+	Type     string    `gorm:"type:varchar(20);not null" json:"type"` // Deposit, Withdrawal
+	Amount   Money     `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Currency string    `gorm:"type:varchar(3);default:'INR'" json:"currency"`
+	Date     time.Time `gorm:"not null" json:"date"`
+	Notes    string    `gorm:"type:text" json:"notes"`
+}
+
+// Validate checks the contribution, treating it as a deposit when no type is
+// given. Future dates are rejected, as goal amounts only count contributions
+// made so far.
+func (c *GoalContribution) Validate() error {
+	if c.Type == "" {
+		c.Type = GoalContributionDeposit
+	}
+	if c.Type != GoalContributionDeposit && c.Type != GoalContributionWithdrawal {
+		return errors.New("Contribution type must be one of Deposit, Withdrawal")
+	}
+	if c.Amount <= 0 {
+		return errors.New("Amount must be greater than 0")
+	}
+	if c.Date.IsZero() {
+		c.Date = time.Now()
+	}
+	if c.Date.After(time.Now()) {
+		return errors.New("Date cannot be in the future")
+	}
+	return nil
+}
+
+// Signed returns the amount, negative for a withdrawal
+func (c GoalContribution) Signed() Money {
+	if c.Type == GoalContributionWithdrawal {
+		return -c.Amount
+	}
+	return c.Amount
+}
+
+// CalculateContributionsTotal calculates the goal's deposits less its withdrawals
+func (g *Goal) CalculateContributionsTotal(contributions []GoalContribution) Money {
+	var total Money
+	for _, c := range contributions {
+		if c.GoalID == g.ID {
+			total += c.Signed()
+		}
+	}
+	return total
+}
+
+//
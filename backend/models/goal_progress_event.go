@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Goal progress event types
+const (
+	GoalEventProgress     = "Progress"
+	GoalEventMilestone    = "Milestone"
+	GoalEventStatusChange = "Status Change"
+)
+
+// GoalMilestones are the progress percentages that raise a milestone event
+var GoalMilestones = []int{25, 50, 75, 100}
+
+// GoalProgressEvent records a point on a goal's way to its target: a change
+// of the amounts, a milestone reached or a change of status
+type GoalProgressEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+
+	UserID        uint    `gorm:"not null;index" json:"user_id,omitempty"`
+	User          User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	GoalID        uint    `gorm:"not null;index" json:"goal_id"`
+	Goal          *Goal   `gorm:"foreignKey:GoalID;constraint:OnDelete:CASCADE" json:"-"`
+	Type          string  `gorm:"type:varchar(20);not null" json:"type"` // Progress, Milestone, Status Change
+	CurrentAmount Money   `gorm:"type:decimal(15,2);default:0" json:"current_amount"`
+	TargetAmount  Money   `gorm:"type:decimal(15,2);default:0" json:"target_amount"` // Inflated target at the time
+	Progress      float64 `gorm:"type:decimal(7,2);default:0" json:"progress"`
+	Milestone     int     `json:"milestone,omitempty"` // 25, 50, 75 or 100
+	FromStatus    string  `gorm:"type:varchar(50)" json:"from_status,omitempty"`
+	ToStatus      string  `gorm:"type:varchar(50)" json:"to_status,omitempty"`
+}
+
+// ProgressEvents returns the events for a change of the goal from previous,
+// which is nil for a new goal: progress when the amounts moved, a milestone
+// for each one reached and a status change. Both goals need their inflation
+// calculated.
+func (g *Goal) ProgressEvents(previous *Goal) []GoalProgressEvent {
+	event := func(eventType string) GoalProgressEvent {
+		return GoalProgressEvent{
+			UserID:        g.UserID,
+			GoalID:        g.ID,
+			Type:          eventType,
+			CurrentAmount: g.CurrentAmount,
+			TargetAmount:  g.InflatedTargetAmount,
+			Progress:      g.RealProgress,
+		}
+	}
+
+	var events []GoalProgressEvent
+	if previous == nil || previous.CurrentAmount != g.CurrentAmount || previous.InflatedTargetAmount != g.InflatedTargetAmount {
+		events = append(events, event(GoalEventProgress))
+	}
+
+	for _, milestone := range GoalMilestones {
+		if g.RealProgress >= float64(milestone) && (previous == nil || previous.RealProgress < float64(milestone)) {
+			e := event(GoalEventMilestone)
+			e.Milestone = milestone
+			events = append(events, e)
+		}
+	}
+
+	if previous != nil && previous.Status != g.Status {
+		e := event(GoalEventStatusChange)
+		e.FromStatus = previous.Status
+		e.ToStatus = g.Status
+		events = append(events, e)
+	}
+	return events
+}
+
+//
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"investment-tracker-backend/finance"
+	"time"
+)
+
+// Goal pace indicators
+const (
+	PaceAhead   = "Ahead"
+	PaceOnTrack = "On Track"
+	PaceBehind  = "Behind"
+)
+
+// maxProjectionMonths caps how far ahead a goal's completion is projected
+const maxProjectionMonths = 100 * 12
+
+// GoalProjection says what it takes to reach a goal by its deadline and when
+// it will be reached at the current rate of saving
+type GoalProjection struct {
+	ExpectedReturn              float64    `json:"expected_return"` // Annual %, weighted by the goal's investments
+	MonthsRemaining             int        `json:"months_remaining"`
+	RequiredMonthlyContribution *Money     `json:"required_monthly_contribution,omitempty"` // Only for goals with a deadline
+	CurrentMonthlyContribution  Money      `json:"current_monthly_contribution"`            // SIPs into the goal's investments plus its monthly contribution
+	ProjectedCompletionDate     *time.Time `json:"projected_completion_date"`               // Nil when the target is never reached at the current rate
+	Pace                        string     `json:"pace"`                                    // Ahead, On Track, Behind
+	MissingFXRates              []string   `json:"missing_fx_rates,omitempty"`              // Currency pairs of investments left out for lack of a rate
+}
+
+// Project works out the goal's projection towards its inflated target from the
+// expected annual return of its investments and the amount currently put
+// towards it each month. A goal finishing within a month of its deadline is
+// on track.
+func (g *Goal) Project(expectedReturn float64, monthly Money, now time.Time) GoalProjection {
+	projection := GoalProjection{
+		ExpectedReturn:             Round2(expectedReturn),
+		CurrentMonthlyContribution: monthly,
+	}
+	current, target := g.CurrentAmount.Float64(), g.InflatedTarget().Float64()
+
+	months, reached := finance.MonthsToTarget(current, target, monthly.Float64(), expectedReturn, maxProjectionMonths)
+	if reached {
+		date := SnapshotDate(now).AddDate(0, months, 0)
+		projection.ProjectedCompletionDate = &date
+	}
+
+	if g.Deadline.IsZero() {
+		projection.Pace = PaceBehind
+		if reached {
+			projection.Pace = PaceOnTrack
+		}
+		return projection
+	}
+
+	projection.MonthsRemaining = g.MonthsRemaining(now)
+	required := NewMoney(finance.RequiredMonthlyContribution(current, target, expectedReturn, projection.MonthsRemaining))
+	projection.RequiredMonthlyContribution = &required
+
+	switch {
+	case !reached || months > projection.MonthsRemaining+1:
+		projection.Pace = PaceBehind
+	case months < projection.MonthsRemaining-1:
+		projection.Pace = PaceAhead
+	default:
+		projection.Pace = PaceOnTrack
+	}
+	return projection
+}
+
+// MonthsRemaining counts the whole months from now until the deadline, or 0 once it has passed
+func (g *Goal) MonthsRemaining(now time.Time) int {
+	date := g.Deadline
+	months := (date.Year()-now.Year())*12 + int(date.Month()-now.Month())
+	if date.Day() < now.Day() {
+		months--
+	}
+	if months < 0 {
+		return 0
+	}
+	return months
+}
+
+//
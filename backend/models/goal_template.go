@@ -0,0 +1,139 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Goal templates that size the target for the user
+const (
+	GoalTemplateEmergencyFund = "Emergency Fund"
+	GoalTemplateRetirement    = "Retirement"
+	GoalTemplateEducation     = "Education"
+)
+
+// Template defaults
+const (
+	defaultEmergencyMonths   = 6
+	defaultWithdrawalRate    = 4.0
+	defaultEducationStartAge = 18
+)
+
+// GoalTemplates lists every goal template
+var GoalTemplates = []string{GoalTemplateEmergencyFund, GoalTemplateRetirement, GoalTemplateEducation}
+
+// GoalTemplateInputs are what a goal template sizes the target from, stored as JSON
+type GoalTemplateInputs struct {
+	Months         int      `json:"months,omitempty"`          // Emergency fund: months of spending to cover
+	WithdrawalRate float64  `json:"withdrawal_rate,omitempty"` // Retirement: share of the corpus withdrawn each year, %
+	CurrentAge     int      `json:"current_age,omitempty"`     // Retirement: with the retirement age, sets the deadline
+	RetirementAge  int      `json:"retirement_age,omitempty"`
+	ChildAge       int      `json:"child_age,omitempty"`      // Education
+	StartAge       int      `json:"start_age,omitempty"`      // Education: age the course starts at
+	CostToday      Money    `json:"cost_today,omitempty"`     // Education: cost of the course in today's money
+	CostInflation  *float64 `json:"cost_inflation,omitempty"` // Education: annual rise in the cost, %; nil uses the category's rate
+
+	AsOf            time.Time `json:"as_of"`                      // Day the inputs were given, which ages count from
+	MonthlySpending Money     `json:"monthly_spending,omitempty"` // Average spending the target was last sized from
+}
+
+// Value implements driver.Valuer
+func (in GoalTemplateInputs) Value() (driver.Value, error) {
+	return json.Marshal(in)
+}
+
+// Scan implements sql.Scanner
+func (in *GoalTemplateInputs) Scan(value interface{}) error {
+	return scanJSON(value, in)
+}
+
+// UsesSpending reports whether the goal's template is sized from the user's spending
+func (g *Goal) UsesSpending() bool {
+	return g.Template == GoalTemplateEmergencyFund || g.Template == GoalTemplateRetirement
+}
+
+// ValidateTemplate checks the goal's template inputs and fills in their defaults
+func (g *Goal) ValidateTemplate() error {
+	if g.Template == "" {
+		return nil
+	}
+	if g.TemplateInputs == nil {
+		g.TemplateInputs = &GoalTemplateInputs{}
+	}
+	in := g.TemplateInputs
+
+	switch g.Template {
+	case GoalTemplateEmergencyFund:
+		if in.Months == 0 {
+			in.Months = defaultEmergencyMonths
+		}
+		if in.Months < 1 || in.Months > 60 {
+			return errors.New("Months must be between 1 and 60")
+		}
+	case GoalTemplateRetirement:
+		if in.WithdrawalRate == 0 {
+			in.WithdrawalRate = defaultWithdrawalRate
+		}
+		if in.WithdrawalRate < 0 || in.WithdrawalRate > 20 {
+			return errors.New("Withdrawal rate must be between 0 and 20")
+		}
+		if in.RetirementAge != 0 && in.RetirementAge <= in.CurrentAge {
+			return errors.New("Retirement age must be greater than the current age")
+		}
+	case GoalTemplateEducation:
+		if in.StartAge == 0 {
+			in.StartAge = defaultEducationStartAge
+		}
+		if in.CostToday <= 0 {
+			return errors.New("Cost today must be greater than 0")
+		}
+		if in.ChildAge < 0 || in.ChildAge >= in.StartAge {
+			return errors.New("Child age must be less than the start age")
+		}
+		if in.CostInflation != nil && (*in.CostInflation < 0 || *in.CostInflation > 50) {
+			return errors.New("Cost inflation must be between 0 and 50")
+		}
+	default:
+		return errors.New("Template must be one of Emergency Fund, Retirement, Education")
+	}
+	return nil
+}
+
+// ApplyTemplate sizes the goal's target from its template inputs and the
+// user's average monthly spending. Targets are in today's money; retirement
+// and education goals are grown by inflation until their deadline.
+func (g *Goal) ApplyTemplate(monthlySpending Money, now time.Time) {
+	if g.Template == "" || g.TemplateInputs == nil {
+		return
+	}
+	in := g.TemplateInputs
+	if in.AsOf.IsZero() {
+		in.AsOf = SnapshotDate(now)
+	}
+
+	switch g.Template {
+	case GoalTemplateEmergencyFund:
+		in.MonthlySpending = monthlySpending
+		g.TargetAmount = monthlySpending.Mul(float64(in.Months))
+	case GoalTemplateRetirement:
+		// The corpus whose yearly withdrawal covers a year of spending
+		in.MonthlySpending = monthlySpending
+		g.TargetAmount = monthlySpending.Mul(12 * 100 / in.WithdrawalRate)
+		g.Category = GoalCategoryRetirement
+		if in.RetirementAge > 0 && in.CurrentAge > 0 {
+			g.Deadline = in.AsOf.AddDate(in.RetirementAge-in.CurrentAge, 0, 0)
+		}
+	case GoalTemplateEducation:
+		g.TargetAmount = in.CostToday
+		g.Category = GoalCategoryEducation
+		if in.CostInflation != nil {
+			rate := *in.CostInflation
+			g.InflationRate = &rate
+		}
+		g.Deadline = in.AsOf.AddDate(in.StartAge-in.ChildAge, 0, 0)
+	}
+}
+
+//
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Income event types
+const (
+	IncomeDividend = "Dividend"
+	IncomeInterest = "Interest"
+	IncomeCoupon   = "Coupon"
+	IncomeOther    = "Other"
+)
+
+// IncomeEvent is a payout received from an investment
+type IncomeEvent struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	UserID       uint        `gorm:"not null;index" json:"user_id,omitempty"`
+	User         User        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	InvestmentID uint        `gorm:"not null;index" json:"investment_id"`
+	Investment   *Investment `gorm:"foreignKey:InvestmentID;constraint:OnDelete:CASCADE" json:"-"`
+	Type         string      `gorm:"type:varchar(20);not null" json:"type"` // Dividend, Interest, Coupon, Other
+	Amount       Money       `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Date         time.Time   `gorm:"not null;index" json:"date"`
+	Notes        string      `gorm:"type:text" json:"notes"`
+}
+
+// Validate checks the income event and defaults its date to today
+func (e *IncomeEvent) Validate() error {
+	switch e.Type {
+	case IncomeDividend, IncomeInterest, IncomeCoupon, IncomeOther:
+	case "":
+		e.Type = IncomeDividend
+	default:
+		return errors.New("Income type must be one of Dividend, Interest, Coupon, Other")
+	}
+	if e.Amount <= 0 {
+		return errors.New("Amount must be greater than 0")
+	}
+	if e.Date.IsZero() {
+		e.Date = time.Now()
+	}
+	return nil
+}
+
+//
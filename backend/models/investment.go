@@ -1,6 +1,9 @@
 package models
 
 import (
+	"investment-tracker-backend/finance"
+	"math"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,33 +17,268 @@ type Investment struct {
 
 	UserID       uint      `gorm:"not null;index" json:"user_id,omitempty"`
 	User         User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
-	GoalID       *uint     `gorm:"index" json:"goal_id,omitempty"` // Link to Goal
+	GoalID       *uint     `gorm:"index" json:"goal_id,omitempty"` // Goal with the largest allocation, see InvestmentGoalAllocation
 	Goal         *Goal     `gorm:"foreignKey:GoalID;constraint:OnDelete:SET NULL" json:"-"`
 	Name         string    `gorm:"type:varchar(255);not null" json:"name"`
 	Type         string    `gorm:"type:varchar(100);not null" json:"type"` // Stocks, Mutual Fund, ETF, FD, PPF, etc.
-	Invested     float64   `gorm:"type:decimal(15,2);not null" json:"invested"`
-	CurrentValue float64   `gorm:"type:decimal(15,2);default:0" json:"current_value"`
+	Invested     Money     `gorm:"type:decimal(15,2);not null" json:"invested"`
+	CurrentValue Money     `gorm:"type:decimal(15,2);default:0" json:"current_value"`
+	Currency     string    `gorm:"type:varchar(3);default:'INR'" json:"currency"`
 	Returns      float64   `gorm:"type:decimal(10,2);default:0" json:"returns"`     // Percentage
-	Status       string    `gorm:"type:varchar(50);default:'Stable'" json:"status"` // Growing, Stable, Declining
+	Status       string    `gorm:"type:varchar(50);default:'Stable'" json:"status"` // Growing, Stable, Declining, Matured
 	PurchaseDate time.Time `json:"purchase_date,omitempty"`
+	Units        float64   `gorm:"type:decimal(20,6);default:0" json:"units"`         // Derived from the transaction ledger
+	RealizedGain Money     `gorm:"type:decimal(15,2);default:0" json:"realized_gain"` // Derived from the transaction ledger
+	Symbol       string    `gorm:"type:varchar(50);index" json:"symbol,omitempty"`    // Ticker, ISIN or scheme code used for pricing
+	Income       Money     `gorm:"type:decimal(15,2);default:0" json:"income"`        // Dividends, interest and coupons received
+	TotalReturns float64   `gorm:"type:decimal(10,2);default:0" json:"total_returns"` // Percentage, price change plus income
+	PeakValue    Money     `gorm:"type:decimal(15,2);default:0" json:"peak_value"`    // Highest current value seen, for drawdown
+
+	// Fixed and recurring deposit terms
+	InterestRate         float64    `gorm:"type:decimal(5,2);default:0" json:"interest_rate,omitempty"` // Annual percentage
+	CompoundingFrequency string     `gorm:"type:varchar(20)" json:"compounding_frequency,omitempty"`    // Monthly, Quarterly, Half-Yearly, Yearly
+	TenureMonths         int        `gorm:"default:0" json:"tenure_months,omitempty"`
+	InstallmentAmount    Money      `gorm:"type:decimal(15,2);default:0" json:"installment_amount,omitempty"` // Monthly installment of a recurring deposit
+	MaturityDate         *time.Time `gorm:"index" json:"maturity_date,omitempty"`
+	MaturityValue        Money      `gorm:"type:decimal(15,2);default:0" json:"maturity_value,omitempty"`
+
+	XIRR *float64 `gorm:"-" json:"xirr,omitempty"` // Annualized money-weighted return, percentage
+	CAGR *float64 `gorm:"-" json:"cagr,omitempty"` // Compound annual growth rate, percentage
 }
 
-// CalculateReturns calculates the return percentage
+// CalculateReturns calculates the price-only and total (price plus income) return percentages
 func (i *Investment) CalculateReturns() {
 	if i.Invested > 0 {
-		i.Returns = ((i.CurrentValue - i.Invested) / i.Invested) * 100
+		i.Returns = (i.CurrentValue - i.Invested).Ratio(i.Invested) * 100
+		i.TotalReturns = (i.CurrentValue - i.Invested + i.Income).Ratio(i.Invested) * 100
+	}
+}
+
+// ApplyIncome totals the income events and ledger dividends received, then
+// recalculates returns
+func (i *Investment) ApplyIncome(events []IncomeEvent, transactions []InvestmentTransaction) {
+	var total Money
+	for _, e := range events {
+		total += e.Amount
+	}
+	for _, t := range transactions {
+		if t.Type == TransactionDividend {
+			total += t.Amount
+		}
+	}
+	i.Income = total
+	i.CalculateReturns()
+}
+
+// ValueAtPrice returns the market value of a number of units at a unit price
+func ValueAtPrice(units, price float64) Money {
+	return NewMoney(units * price)
+}
+
+// AddPurchase adds an amount invested, and the units bought for it at price
+// when known, to a holding without a transaction ledger. Holdings already
+// tracking units are revalued at the new price. Holdings entered by value
+// alone keep not tracking units and gain the value of the purchase, so their
+// existing value is kept.
+func (i *Investment) AddPurchase(amount Money, units, price float64) {
+	i.Invested += amount
+	switch {
+	case units > 0 && i.Units > 0:
+		i.Units += units
+		i.CurrentValue = ValueAtPrice(i.Units, price)
+	case units > 0:
+		i.CurrentValue += ValueAtPrice(units, price)
+	default:
+		i.CurrentValue += amount
+	}
+	i.CalculateReturns()
+}
+
+// ApplyLedger derives units held, invested cost and realized gain from the
+// transaction ledger using average cost, then recalculates returns
+func (i *Investment) ApplyLedger(transactions []InvestmentTransaction) {
+	if len(transactions) == 0 {
+		return
+	}
+
+	sorted := sortLedger(transactions)
+	units, lastPrice := 0.0, 0.0
+	var cost, realized Money
+	tracksUnits := false
+	for _, t := range sorted {
+		switch t.Type {
+		case TransactionBuy:
+			units += t.Units
+			cost += t.Amount
+			if t.Units > 0 {
+				tracksUnits = true
+			}
+		case TransactionSell:
+			if units > 0 && t.Units > 0 {
+				// Only the units actually held are sold, for their share of the proceeds
+				sold := math.Min(t.Units, units)
+				soldCost := cost.Mul(sold / units)
+				cost -= soldCost
+				realized += t.Amount.Mul(sold/t.Units) - soldCost
+				units -= sold
+			}
+		case TransactionDividend:
+			realized += t.Amount
+		case TransactionFee:
+			realized -= t.Amount
+		case TransactionSplit:
+			units *= t.Units
+			if lastPrice > 0 {
+				lastPrice /= t.Units
+			}
+			continue
+		}
+		if t.Price > 0 && (t.Type == TransactionBuy || t.Type == TransactionSell) {
+			lastPrice = t.Price
+		}
+	}
+
+	// Keep the market value in step with the units held
+	if tracksUnits {
+		switch {
+		case units <= 0:
+			i.CurrentValue = 0
+		case i.Units > 0:
+			i.CurrentValue = i.CurrentValue.Mul(units / i.Units)
+		case lastPrice > 0:
+			i.CurrentValue = ValueAtPrice(units, lastPrice)
+		}
+	}
+
+	i.Units = units
+	i.Invested = cost
+	i.RealizedGain = realized
+	i.Returns = 0
+	i.TotalReturns = 0
+	i.CalculateReturns()
+}
+
+// CashFlows returns the dated money movements of the investment up to asOf,
+// closing with its current value
+func (i *Investment) CashFlows(transactions []InvestmentTransaction, asOf time.Time) []finance.CashFlow {
+	flows := i.ContributionFlows(transactions)
+	if i.CurrentValue > 0 {
+		flows = append(flows, finance.CashFlow{Date: asOf, Amount: i.CurrentValue.Float64()})
+	}
+	return flows
+}
+
+// ContributionFlows returns the money paid into and taken out of the
+// investment. Without a ledger the invested amount is treated as a single
+// outflow on the purchase date.
+func (i *Investment) ContributionFlows(transactions []InvestmentTransaction) []finance.CashFlow {
+	var flows []finance.CashFlow
+	if len(transactions) == 0 {
+		if i.Invested > 0 && !i.PurchaseDate.IsZero() {
+			flows = append(flows, finance.CashFlow{Date: i.PurchaseDate, Amount: -i.Invested.Float64()})
+		}
 	}
+	for _, t := range transactions {
+		switch t.Type {
+		case TransactionBuy, TransactionFee:
+			flows = append(flows, finance.CashFlow{Date: t.Date, Amount: -t.Amount.Float64()})
+		case TransactionSell, TransactionDividend:
+			flows = append(flows, finance.CashFlow{Date: t.Date, Amount: t.Amount.Float64()})
+		}
+	}
+	return flows
+}
+
+// CalculateAnnualizedReturns fills in XIRR and CAGR from the investment's cash flows
+func (i *Investment) CalculateAnnualizedReturns(transactions []InvestmentTransaction, asOf time.Time) {
+	i.XIRR, i.CAGR = AnnualizedReturns(i.CashFlows(transactions, asOf), asOf)
 }
 
-// UpdateStatus updates the status based on returns
+// AnnualizedReturns calculates XIRR and CAGR percentages for a set of cash
+// flows, leaving either nil when it cannot be computed
+func AnnualizedReturns(flows []finance.CashFlow, asOf time.Time) (xirr *float64, cagr *float64) {
+	if rate, err := finance.XIRR(flows); err == nil {
+		pct := Round2(rate * 100)
+		xirr = &pct
+	}
+
+	invested, final := 0.0, 0.0
+	for _, f := range flows {
+		if f.Amount < 0 {
+			invested -= f.Amount
+		} else {
+			final += f.Amount
+		}
+	}
+	if rate, err := finance.CAGR(invested, final, finance.WeightedHoldingYears(flows, asOf)); err == nil {
+		pct := Round2(rate * 100)
+		cagr = &pct
+	}
+	return xirr, cagr
+}
+
+// DepositKind returns "FD" or "RD" for fixed and recurring deposits, or "" otherwise
+func (i *Investment) DepositKind() string {
+	t := strings.ToLower(i.Type)
+	switch {
+	case hasWord(t, "rd") || strings.Contains(t, "recurring deposit"):
+		return "RD"
+	case hasWord(t, "fd") || strings.Contains(t, "fixed deposit"):
+		return "FD"
+	default:
+		return ""
+	}
+}
+
+// AccrueDeposit brings a fixed or recurring deposit's value up to asOf (or its
+// maturity date, if earlier) and records the maturity date and value. It
+// returns false for investments that are not interest-bearing deposits.
+func (i *Investment) AccrueDeposit(asOf time.Time) bool {
+	kind := i.DepositKind()
+	if kind == "" || i.InterestRate <= 0 || i.PurchaseDate.IsZero() {
+		return false
+	}
+
+	if i.MaturityDate == nil && i.TenureMonths > 0 {
+		maturity := i.PurchaseDate.AddDate(0, i.TenureMonths, 0)
+		i.MaturityDate = &maturity
+	}
+	if i.MaturityDate != nil && i.MaturityDate.Before(asOf) {
+		asOf = *i.MaturityDate
+	}
+
+	periods := finance.CompoundingPeriods(i.CompoundingFrequency)
+	if kind == "RD" && i.InstallmentAmount > 0 {
+		installment := i.InstallmentAmount.Float64()
+		value, paid := finance.RecurringDepositValue(installment, i.InterestRate, periods, i.PurchaseDate, asOf, i.TenureMonths)
+		i.Invested = i.InstallmentAmount * Money(paid)
+		i.CurrentValue = NewMoney(value)
+		if i.MaturityDate != nil {
+			maturityValue, _ := finance.RecurringDepositValue(installment, i.InterestRate, periods, i.PurchaseDate, *i.MaturityDate, i.TenureMonths)
+			i.MaturityValue = NewMoney(maturityValue)
+		}
+	} else {
+		principal := i.Invested.Float64()
+		i.CurrentValue = NewMoney(finance.FixedDepositValue(principal, i.InterestRate, periods, i.PurchaseDate, asOf))
+		if i.MaturityDate != nil {
+			i.MaturityValue = NewMoney(finance.FixedDepositValue(principal, i.InterestRate, periods, i.PurchaseDate, *i.MaturityDate))
+		}
+	}
+
+	i.CalculateReturns()
+	return true
+}
+
+// UpdateStatus updates the status based on returns using the default status rules
 func (i *Investment) UpdateStatus() {
-	if i.Returns >= 10 {
-		i.Status = "Growing"
-	} else if i.Returns >= 0 {
+	if !i.ApplyStatusRules(DefaultStatusRules()) {
 		i.Status = "Stable"
-	} else {
-		i.Status = "Declining"
 	}
 }
 
+// Round2 rounds an amount, percentage or unit count to two decimal places
+func Round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
 //
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// InvestmentGoalAllocation assigns a percentage of an investment's value to a goal,
+// so that one holding can be shared between several goals
+type InvestmentGoalAllocation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID       uint        `gorm:"not null;index" json:"user_id,omitempty"`
+	User         User        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	InvestmentID uint        `gorm:"not null;uniqueIndex:idx_allocation_investment_goal" json:"investment_id"`
+	Investment   *Investment `gorm:"foreignKey:InvestmentID;constraint:OnDelete:CASCADE" json:"-"`
+	GoalID       uint        `gorm:"not null;uniqueIndex:idx_allocation_investment_goal;index" json:"goal_id"`
+	Goal         *Goal       `gorm:"foreignKey:GoalID;constraint:OnDelete:CASCADE" json:"-"`
+	Percent      float64     `gorm:"type:decimal(5,2);not null" json:"percent"` // Share of the investment's value, 0-100
+}
+
+// Share returns the part of an investment's value allocated to the goal
+func (a InvestmentGoalAllocation) Share(value Money) Money {
+	return value.Mul(a.Percent / 100)
+}
+
+// ValidateAllocations checks that each allocation is positive and that an
+// investment's allocations add up to at most 100%
+func ValidateAllocations(allocations []InvestmentGoalAllocation) error {
+	var total float64
+	for _, a := range allocations {
+		if a.Percent <= 0 || a.Percent > 100 {
+			return errors.New("Allocation percent must be greater than 0 and at most 100")
+		}
+		total += a.Percent
+	}
+	if Round2(total) > 100 {
+		return fmt.Errorf("Allocations add up to %.2f%%, which is more than 100%%", total)
+	}
+	return nil
+}
+
+// PrimaryGoalID returns the goal holding the largest allocation, or nil when
+// there are none
+func PrimaryGoalID(allocations []InvestmentGoalAllocation) *uint {
+	var primary *InvestmentGoalAllocation
+	for i := range allocations {
+		if primary == nil || allocations[i].Percent > primary.Percent {
+			primary = &allocations[i]
+		}
+	}
+	if primary == nil {
+		return nil
+	}
+	goalID := primary.GoalID
+	return &goalID
+}
+
+//
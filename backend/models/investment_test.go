@@ -0,0 +1,132 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddPurchase(t *testing.T) {
+	tests := []struct {
+		name       string
+		holding    Investment
+		amount     Money
+		units      float64
+		price      float64
+		wantValue  Money
+		wantUnits  float64
+		wantInvest Money
+	}{
+		{
+			name:       "value only holding keeps its value",
+			holding:    Investment{Invested: NewMoney(100000), CurrentValue: NewMoney(120000)},
+			amount:     NewMoney(5000),
+			units:      100,
+			price:      52,
+			wantValue:  NewMoney(125200),
+			wantUnits:  0,
+			wantInvest: NewMoney(105000),
+		},
+		{
+			name:       "unit holding is revalued at the new price",
+			holding:    Investment{Invested: NewMoney(10000), CurrentValue: NewMoney(11000), Units: 200},
+			amount:     NewMoney(5000),
+			units:      100,
+			price:      50,
+			wantValue:  NewMoney(15000),
+			wantUnits:  300,
+			wantInvest: NewMoney(15000),
+		},
+		{
+			name:       "without a price the purchase is valued at cost",
+			holding:    Investment{Invested: NewMoney(10000), CurrentValue: NewMoney(9000), Units: 200},
+			amount:     NewMoney(5000),
+			wantValue:  NewMoney(14000),
+			wantUnits:  200,
+			wantInvest: NewMoney(15000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := tt.holding
+			inv.AddPurchase(tt.amount, tt.units, tt.price)
+			if inv.CurrentValue != tt.wantValue {
+				t.Errorf("CurrentValue = %s, want %s", inv.CurrentValue, tt.wantValue)
+			}
+			if inv.Units != tt.wantUnits {
+				t.Errorf("Units = %v, want %v", inv.Units, tt.wantUnits)
+			}
+			if inv.Invested != tt.wantInvest {
+				t.Errorf("Invested = %s, want %s", inv.Invested, tt.wantInvest)
+			}
+		})
+	}
+}
+
+func TestApplyLedgerSellBeyondHolding(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	ledger := []InvestmentTransaction{
+		{ID: 1, Type: TransactionBuy, Units: 10, Price: 100, Amount: NewMoney(1000), Date: day(1)},
+		{ID: 2, Type: TransactionSell, Units: 20, Price: 150, Amount: NewMoney(3000), Date: day(2)},
+	}
+
+	var inv Investment
+	inv.ApplyLedger(ledger)
+	// 10 units held are sold for half of the proceeds
+	if want := NewMoney(500); inv.RealizedGain != want {
+		t.Errorf("RealizedGain = %s, want %s", inv.RealizedGain, want)
+	}
+	if inv.Units != 0 || inv.Invested != 0 {
+		t.Errorf("Units = %v, Invested = %s, want both 0", inv.Units, inv.Invested)
+	}
+
+	if held := UnitsHeld(ledger[:1], day(1)); held != 10 {
+		t.Errorf("UnitsHeld = %v, want 10", held)
+	}
+	if held := UnitsHeld(ledger[:1], day(1).Add(-time.Hour)); held != 0 {
+		t.Errorf("UnitsHeld before the buy = %v, want 0", held)
+	}
+}
+
+func TestCheckLedger(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	buy := InvestmentTransaction{ID: 1, Type: TransactionBuy, Units: 10, Amount: NewMoney(1000), Date: day(1)}
+	laterBuy := InvestmentTransaction{ID: 2, Type: TransactionBuy, Units: 10, Amount: NewMoney(1000), Date: day(10)}
+	sell := InvestmentTransaction{ID: 3, Type: TransactionSell, Units: 15, Amount: NewMoney(1800), Date: day(20)}
+
+	tests := []struct {
+		name    string
+		ledger  []InvestmentTransaction
+		wantErr bool
+	}{
+		{name: "sale within holdings", ledger: []InvestmentTransaction{buy, laterBuy, sell}},
+		{
+			name:    "removing a buy oversells a later sale",
+			ledger:  []InvestmentTransaction{buy, sell},
+			wantErr: true,
+		},
+		{
+			name:    "backdated sale leaves a later one oversold",
+			ledger:  []InvestmentTransaction{buy, laterBuy, sell, {Type: TransactionSell, Units: 8, Amount: NewMoney(800), Date: day(5)}},
+			wantErr: true,
+		},
+		{
+			name:   "split before a sale",
+			ledger: []InvestmentTransaction{buy, {ID: 4, Type: TransactionSplit, Units: 2, Date: day(15)}, sell},
+		},
+		{
+			name:   "unsaved sale on the same day comes after saved ones",
+			ledger: []InvestmentTransaction{{Type: TransactionSell, Units: 10, Amount: NewMoney(1000), Date: day(1)}, buy},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckLedger(tt.ledger); (err != nil) != tt.wantErr {
+				t.Errorf("CheckLedger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+//
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Transaction types recorded in an investment's ledger
+const (
+	TransactionBuy      = "Buy"
+	TransactionSell     = "Sell"
+	TransactionDividend = "Dividend"
+	TransactionFee      = "Fee"
+	TransactionSplit    = "Split"
+)
+
+type InvestmentTransaction struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	UserID       uint        `gorm:"not null;index" json:"user_id,omitempty"`
+	User         User        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	InvestmentID uint        `gorm:"not null;index" json:"investment_id"`
+	Investment   *Investment `gorm:"foreignKey:InvestmentID;constraint:OnDelete:CASCADE" json:"-"`
+	Type         string      `gorm:"type:varchar(20);not null" json:"type"`      // Buy, Sell, Dividend, Fee, Split
+	Units        float64     `gorm:"type:decimal(20,6);default:0" json:"units"`  // For Split: new units per old unit (5 for a 1:5 split)
+	Price        float64     `gorm:"type:decimal(20,6);default:0" json:"price"`  // Price per unit
+	Amount       Money       `gorm:"type:decimal(15,2);default:0" json:"amount"` // Cash paid or received
+	Date         time.Time   `gorm:"not null" json:"date"`
+	Notes        string      `gorm:"type:text" json:"notes"`
+}
+
+// Validate checks the transaction and fills in the amount for buys and sells
+func (t *InvestmentTransaction) Validate() error {
+	switch t.Type {
+	case TransactionBuy, TransactionSell:
+		if t.Units < 0 || t.Price < 0 || t.Amount < 0 {
+			return errors.New("Units, price and amount cannot be negative")
+		}
+		if t.Amount == 0 {
+			t.Amount = NewMoney(t.Units * t.Price)
+		}
+		if t.Amount <= 0 {
+			return errors.New("Amount (or units and price) must be greater than 0")
+		}
+		if t.Type == TransactionSell && t.Units <= 0 {
+			return errors.New("Units sold must be greater than 0")
+		}
+	case TransactionDividend, TransactionFee:
+		if t.Amount <= 0 {
+			return errors.New("Amount must be greater than 0")
+		}
+	case TransactionSplit:
+		if t.Units <= 0 {
+			return errors.New("Split ratio (units) must be greater than 0")
+		}
+	default:
+		return errors.New("Transaction type must be one of Buy, Sell, Dividend, Fee, Split")
+	}
+
+	if t.Date.IsZero() {
+		t.Date = time.Now()
+	}
+	return nil
+}
+
+// CheckLedger replays a ledger in date order and returns an error at the
+// first sale of more units than are held at that point
+func CheckLedger(transactions []InvestmentTransaction) error {
+	units := 0.0
+	for _, t := range sortLedger(transactions) {
+		switch t.Type {
+		case TransactionBuy:
+			units += t.Units
+		case TransactionSell:
+			if t.Units > units+1e-9 {
+				return fmt.Errorf("Cannot sell %g units on %s, only %g are held then", t.Units, t.Date.Format("2006-01-02"), units)
+			}
+			units -= t.Units
+		case TransactionSplit:
+			units *= t.Units
+		}
+	}
+	return nil
+}
+
+// sortLedger returns a copy of the transactions in date order. Transactions
+// on the same day keep the order they were recorded in, with unsaved ones last.
+func sortLedger(transactions []InvestmentTransaction) []InvestmentTransaction {
+	sorted := make([]InvestmentTransaction, len(transactions))
+	copy(sorted, transactions)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if !sorted[a].Date.Equal(sorted[b].Date) {
+			return sorted[a].Date.Before(sorted[b].Date)
+		}
+		if sorted[a].ID == 0 || sorted[b].ID == 0 {
+			return sorted[b].ID == 0 && sorted[a].ID != 0
+		}
+		return sorted[a].ID < sorted[b].ID
+	})
+	return sorted
+}
+
+// UnitsHeld returns the units a ledger holds once its transactions up to at are applied
+func UnitsHeld(transactions []InvestmentTransaction, at time.Time) float64 {
+	var past []InvestmentTransaction
+	for _, t := range transactions {
+		if !t.Date.After(at) {
+			past = append(past, t)
+		}
+	}
+	var holding Investment
+	holding.ApplyLedger(past)
+	return holding.Units
+}
+
+//
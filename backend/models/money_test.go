@@ -0,0 +1,121 @@
+package models
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+// maxTestMinorUnits keeps generated amounts well inside what a float64 and a
+// decimal(15,2) column hold exactly
+const maxTestMinorUnits = 1e15
+
+// testMoney maps a generated number onto an amount within range
+func testMoney(n int64) Money {
+	return Money(n % maxTestMinorUnits)
+}
+
+func TestNewMoneyKeepsMinorUnits(t *testing.T) {
+	f := func(n int64) bool {
+		m := testMoney(n)
+		return NewMoney(m.Float64()) == m
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMulRounding(t *testing.T) {
+	f := func(n int64) bool {
+		m := testMoney(n)
+		return m.Mul(1) == m && m.Mul(0) == 0 && m.Mul(-1) == -m && m.Mul(2) == m+m
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	// Halves round away from zero
+	if got := Money(5).Mul(0.5); got != 3 {
+		t.Errorf("Money(5).Mul(0.5) = %d, want 3", got)
+	}
+	if got := Money(-5).Mul(0.5); got != -3 {
+		t.Errorf("Money(-5).Mul(0.5) = %d, want -3", got)
+	}
+}
+
+func TestMoneyStringRoundTrip(t *testing.T) {
+	f := func(n int64) bool {
+		m := testMoney(n)
+		parsed, err := ParseMoney(m.String())
+		return err == nil && parsed == m
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoneyJSONRoundTrip(t *testing.T) {
+	f := func(n int64) bool {
+		m := testMoney(n)
+		data, err := json.Marshal(struct{ Amount Money }{m})
+		if err != nil {
+			return false
+		}
+		var decoded struct{ Amount Money }
+		return json.Unmarshal(data, &decoded) == nil && decoded.Amount == m
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMoneySQLRoundTrip(t *testing.T) {
+	f := func(n int64) bool {
+		m := testMoney(n)
+		value, err := m.Value()
+		if err != nil {
+			return false
+		}
+
+		// Drivers hand decimals back as bytes or strings
+		var fromString, fromBytes Money
+		if fromString.Scan(value) != nil || fromBytes.Scan([]byte(value.(string))) != nil {
+			return false
+		}
+		return fromString == m && fromBytes == m
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBudgetExpensesAddAndRemove(t *testing.T) {
+	// Expenses are added to and removed from a budget's total one at a time,
+	// in any order, the way the expense handlers do it
+	f := func(income int64, amounts []float64, seed int64) bool {
+		budget := Budget{Income: testMoney(income), TotalExpenses: NewMoney(1234.56)}
+		budget.CalculateSavings()
+		before := budget
+
+		expenses := make([]Money, len(amounts))
+		for i, amount := range amounts {
+			// Entered amounts and FX-converted ones both land on whole minor units
+			expenses[i] = NewMoney(float64(int64(amount*1e6)%1e9) / 100).Mul(1.0 / 83.27)
+			budget.TotalExpenses += expenses[i]
+			budget.CalculateSavings()
+		}
+
+		r := rand.New(rand.NewSource(seed))
+		for _, i := range r.Perm(len(expenses)) {
+			budget.TotalExpenses -= expenses[i]
+			budget.CalculateSavings()
+		}
+		return budget.TotalExpenses == before.TotalExpenses && budget.Savings == before.Savings
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+//
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Goal priorities
+const (
+	GoalPriorityHigh   = "High"
+	GoalPriorityMedium = "Medium"
+	GoalPriorityLow    = "Low"
+)
+
+// PriorityRank orders goal priorities from High (0) to Low, with unknown priorities last
+func PriorityRank(priority string) int {
+	switch priority {
+	case GoalPriorityHigh:
+		return 0
+	case GoalPriorityMedium:
+		return 1
+	case GoalPriorityLow:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// PlannedContribution is the part of a month's budget savings set aside for a
+// goal by the savings planner. Committing a plan again replaces the month's rows.
+type PlannedContribution struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID   uint    `gorm:"not null;index:idx_planned_contribution_user_month" json:"user_id,omitempty"`
+	User     User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Month    string  `gorm:"type:varchar(7);not null;index:idx_planned_contribution_user_month" json:"month"` // Format: "2024-01"
+	BudgetID uint    `gorm:"not null;index" json:"budget_id"`
+	Budget   *Budget `gorm:"foreignKey:BudgetID;constraint:OnDelete:CASCADE" json:"-"`
+	GoalID   uint    `gorm:"not null;index" json:"goal_id"`
+	Goal     *Goal   `gorm:"foreignKey:GoalID;constraint:OnDelete:CASCADE" json:"-"`
+	Strategy string  `gorm:"type:varchar(20);not null" json:"strategy"` // priority, proportional, deadline
+	Amount   Money   `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Currency string  `gorm:"type:varchar(3);default:'INR'" json:"currency"` // The goal's currency
+}
+
+//
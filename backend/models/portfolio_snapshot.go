@@ -0,0 +1,81 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// PortfolioSnapshot records a user's portfolio totals at the end of a day.
+// Snapshots are overwritten in place when a day is recomputed.
+type PortfolioSnapshot struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	UserID        uint            `gorm:"not null;uniqueIndex:idx_snapshot_user_date" json:"user_id,omitempty"`
+	User          User            `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Date          time.Time       `gorm:"type:date;not null;uniqueIndex:idx_snapshot_user_date" json:"date"`
+	Currency      string          `gorm:"type:varchar(3);default:'INR'" json:"currency"` // The user's base currency
+	TotalInvested Money           `gorm:"type:decimal(15,2);default:0" json:"total_invested"`
+	CurrentValue  Money           `gorm:"type:decimal(15,2);default:0" json:"current_value"`
+	TypeBreakdown AmountBreakdown `gorm:"type:jsonb" json:"type_breakdown"` // Current value per investment type
+	GoalProgress  GoalSnapshots   `gorm:"type:jsonb" json:"goal_progress"`
+}
+
+// GoalSnapshot is a goal's progress captured in a portfolio snapshot
+type GoalSnapshot struct {
+	GoalID        uint    `json:"goal_id"`
+	Name          string  `json:"name"`
+	TargetAmount  Money   `json:"target_amount"`
+	CurrentAmount Money   `json:"current_amount"`
+	Progress      float64 `json:"progress"`
+}
+
+// AmountBreakdown maps a label (such as an investment type) to an amount, stored as JSON
+type AmountBreakdown map[string]Money
+
+// GoalSnapshots is a list of goal progress entries, stored as JSON
+type GoalSnapshots []GoalSnapshot
+
+// SnapshotDate truncates a time to the calendar day used for snapshots
+func SnapshotDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Value implements driver.Valuer
+func (b AmountBreakdown) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+// Scan implements sql.Scanner
+func (b *AmountBreakdown) Scan(value interface{}) error {
+	return scanJSON(value, b)
+}
+
+// Value implements driver.Valuer
+func (g GoalSnapshots) Value() (driver.Value, error) {
+	return json.Marshal(g)
+}
+
+// Scan implements sql.Scanner
+func (g *GoalSnapshots) Scan(value interface{}) error {
+	return scanJSON(value, g)
+}
+
+// scanJSON decodes a JSON column into dest
+func scanJSON(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return errors.New("unsupported type for JSON column")
+	}
+}
+
+//
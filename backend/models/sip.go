@@ -0,0 +1,134 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SIP installment frequencies
+const (
+	SIPWeekly    = "Weekly"
+	SIPMonthly   = "Monthly"
+	SIPQuarterly = "Quarterly"
+	SIPYearly    = "Yearly"
+)
+
+// SIP is a systematic investment plan: a fixed amount invested into an
+// investment on a recurring schedule
+type SIP struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	UserID       uint        `gorm:"not null;index" json:"user_id,omitempty"`
+	User         User        `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	InvestmentID uint        `gorm:"not null;index" json:"investment_id"`
+	Investment   *Investment `gorm:"foreignKey:InvestmentID;constraint:OnDelete:CASCADE" json:"-"`
+	Amount       Money       `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Frequency    string      `gorm:"type:varchar(20);default:'Monthly'" json:"frequency"` // Weekly, Monthly, Quarterly, Yearly
+	DayOfMonth   int         `gorm:"default:1" json:"day_of_month"`                       // Clamped to the last day of shorter months
+	StartDate    time.Time   `gorm:"not null" json:"start_date"`
+	EndDate      *time.Time  `json:"end_date,omitempty"`
+	LastPostedOn *time.Time  `json:"last_posted_on,omitempty"` // Due date of the last installment posted
+	Active       bool        `gorm:"default:true" json:"active"`
+}
+
+// Validate checks the SIP and fills in the default frequency and day of month
+func (s *SIP) Validate() error {
+	if s.Amount <= 0 {
+		return errors.New("Amount must be greater than 0")
+	}
+	switch s.Frequency {
+	case SIPWeekly, SIPMonthly, SIPQuarterly, SIPYearly:
+	case "":
+		s.Frequency = SIPMonthly
+	default:
+		return errors.New("Frequency must be one of Weekly, Monthly, Quarterly, Yearly")
+	}
+	if s.StartDate.IsZero() {
+		s.StartDate = SnapshotDate(time.Now())
+	}
+	if s.DayOfMonth == 0 {
+		s.DayOfMonth = s.StartDate.Day()
+	}
+	if s.DayOfMonth < 1 || s.DayOfMonth > 31 {
+		return errors.New("Day of month must be between 1 and 31")
+	}
+	if s.EndDate != nil && s.EndDate.Before(s.StartDate) {
+		return errors.New("End date cannot be before the start date")
+	}
+	return nil
+}
+
+// MonthlyAmount returns the SIP amount as an average per month
+func (s *SIP) MonthlyAmount() Money {
+	switch s.Frequency {
+	case SIPWeekly:
+		return s.Amount.Mul(52.0 / 12)
+	case SIPQuarterly:
+		return s.Amount.Mul(1.0 / 3)
+	case SIPYearly:
+		return s.Amount.Mul(1.0 / 12)
+	default:
+		return s.Amount
+	}
+}
+
+// DueDates returns the installment dates after the last one posted (or from
+// the start date) up to and including until
+func (s *SIP) DueDates(until time.Time) []time.Time {
+	if s.EndDate != nil && s.EndDate.Before(until) {
+		until = *s.EndDate
+	}
+
+	var dates []time.Time
+	for n := 0; ; n++ {
+		date := s.installmentDate(n)
+		if date.After(until) {
+			return dates
+		}
+		if s.LastPostedOn != nil && !date.After(*s.LastPostedOn) {
+			continue
+		}
+		dates = append(dates, date)
+	}
+}
+
+// installmentDate returns the date of the nth installment (from 0), the first
+// being on or after the start date
+func (s *SIP) installmentDate(n int) time.Time {
+	start := SnapshotDate(s.StartDate)
+	if s.Frequency == SIPWeekly {
+		return start.AddDate(0, 0, 7*n)
+	}
+
+	step := 1
+	switch s.Frequency {
+	case SIPQuarterly:
+		step = 3
+	case SIPYearly:
+		step = 12
+	}
+	first := 0
+	if s.dayInMonth(start.Year(), start.Month(), 0).Before(start) {
+		first = 1
+	}
+	return s.dayInMonth(start.Year(), start.Month(), first+n*step)
+}
+
+// dayInMonth returns the SIP's day of month, offset by a number of months
+// from the given month and clamped to that month's length
+func (s *SIP) dayInMonth(year int, month time.Month, offset int) time.Time {
+	first := time.Date(year, month+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := s.DayOfMonth
+	if day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+//
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Metrics a status rule can test
+const (
+	MetricReturns          = "returns"           // Price return percentage
+	MetricAnnualizedReturn = "annualized_return" // XIRR, or annualized return since purchase
+	MetricDrawdown         = "drawdown"          // Percentage below the peak value
+)
+
+// StatusRule sets an investment's status when a metric compares to a
+// threshold. Rules for the investment's type are tried before rules for all
+// types; within each, lower priority numbers go first.
+type StatusRule struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	UserID         uint    `gorm:"not null;index" json:"user_id,omitempty"`
+	User           User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	InvestmentType string  `gorm:"type:varchar(100)" json:"investment_type"` // Empty applies to every type
+	Metric         string  `gorm:"type:varchar(30);not null" json:"metric"`  // returns, annualized_return, drawdown
+	Operator       string  `gorm:"type:varchar(2);not null" json:"operator"` // >=, >, <=, <
+	Threshold      float64 `gorm:"type:decimal(10,2);not null" json:"threshold"`
+	Status         string  `gorm:"type:varchar(50);not null" json:"status"`
+	Priority       int     `gorm:"default:0" json:"priority"`
+}
+
+// DefaultStatusRules reproduce the built-in thresholds: Growing at 10% or
+// more, Stable from 0%, Declining below 0%
+func DefaultStatusRules() []StatusRule {
+	return []StatusRule{
+		{Metric: MetricReturns, Operator: ">=", Threshold: 10, Status: "Growing", Priority: 1},
+		{Metric: MetricReturns, Operator: ">=", Threshold: 0, Status: "Stable", Priority: 2},
+		{Metric: MetricReturns, Operator: "<", Threshold: 0, Status: "Declining", Priority: 3},
+	}
+}
+
+// Validate checks the rule's metric, operator and status
+func (r *StatusRule) Validate() error {
+	switch r.Metric {
+	case MetricReturns, MetricAnnualizedReturn, MetricDrawdown:
+	default:
+		return errors.New("Metric must be one of returns, annualized_return, drawdown")
+	}
+	switch r.Operator {
+	case ">=", ">", "<=", "<":
+	default:
+		return errors.New("Operator must be one of >=, >, <=, <")
+	}
+	r.Status = strings.TrimSpace(r.Status)
+	if r.Status == "" {
+		return errors.New("Status is required")
+	}
+	r.InvestmentType = strings.TrimSpace(r.InvestmentType)
+	return nil
+}
+
+// Matches reports whether the rule's condition holds for the investment
+func (r StatusRule) Matches(i *Investment) bool {
+	var value float64
+	switch r.Metric {
+	case MetricReturns:
+		value = i.Returns
+	case MetricAnnualizedReturn:
+		annualized, ok := i.AnnualizedReturn(time.Now())
+		if !ok {
+			return false
+		}
+		value = annualized
+	case MetricDrawdown:
+		value = i.Drawdown()
+	default:
+		return false
+	}
+
+	switch r.Operator {
+	case ">=":
+		return value >= r.Threshold
+	case ">":
+		return value > r.Threshold
+	case "<=":
+		return value <= r.Threshold
+	case "<":
+		return value < r.Threshold
+	}
+	return false
+}
+
+// ApplyStatusRules sets the status from the first matching rule, after
+// recording the peak value, and reports whether any rule matched. Matured
+// deposits keep the Matured status.
+func (i *Investment) ApplyStatusRules(rules []StatusRule) bool {
+	if i.CurrentValue > i.PeakValue {
+		i.PeakValue = i.CurrentValue
+	}
+	if i.MaturityDate != nil && !i.MaturityDate.After(time.Now()) {
+		i.Status = "Matured"
+		return true
+	}
+
+	var applicable []StatusRule
+	for _, rule := range rules {
+		if rule.InvestmentType == "" || strings.EqualFold(rule.InvestmentType, i.Type) {
+			applicable = append(applicable, rule)
+		}
+	}
+	sort.SliceStable(applicable, func(a, b int) bool {
+		aTyped, bTyped := applicable[a].InvestmentType != "", applicable[b].InvestmentType != ""
+		if aTyped != bTyped {
+			return aTyped
+		}
+		return applicable[a].Priority < applicable[b].Priority
+	})
+
+	for _, rule := range applicable {
+		if rule.Matches(i) {
+			i.Status = rule.Status
+			return true
+		}
+	}
+	return false
+}
+
+// Drawdown returns how far, as a percentage, the current value is below the peak value
+func (i *Investment) Drawdown() float64 {
+	if i.PeakValue <= 0 || i.CurrentValue >= i.PeakValue {
+		return 0
+	}
+	return (i.PeakValue - i.CurrentValue).Ratio(i.PeakValue) * 100
+}
+
+// AnnualizedReturn returns the XIRR when it has been calculated, otherwise the
+// price return annualized over the time since purchase
+func (i *Investment) AnnualizedReturn(asOf time.Time) (float64, bool) {
+	if i.XIRR != nil {
+		return *i.XIRR, true
+	}
+	years := asOf.Sub(i.PurchaseDate).Hours() / 24 / 365
+	if i.Invested <= 0 || i.PurchaseDate.IsZero() || years <= 0 {
+		return 0, false
+	}
+	growth := i.CurrentValue.Ratio(i.Invested)
+	if growth <= 0 {
+		return -100, true
+	}
+	return (math.Pow(growth, 1/years) - 1) * 100, true
+}
+
+//
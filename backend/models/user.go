@@ -12,11 +12,12 @@ type User struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
-	Email           string  `gorm:"uniqueIndex;not null" json:"email"`
-	Name            string  `gorm:"type:varchar(255)" json:"name,omitempty"`
-	MonthlyIncome   float64 `gorm:"type:decimal(15,2);default:0" json:"monthly_income,omitempty"`
-	MonthlyExpenses float64 `gorm:"type:decimal(15,2);default:0" json:"monthly_expenses,omitempty"`
-	MonthlySavings  float64 `gorm:"type:decimal(15,2);default:0" json:"monthly_savings,omitempty"`
+	Email           string `gorm:"uniqueIndex;not null" json:"email"`
+	Name            string `gorm:"type:varchar(255)" json:"name,omitempty"`
+	MonthlyIncome   Money  `gorm:"type:decimal(15,2);default:0" json:"monthly_income,omitempty"`
+	MonthlyExpenses Money  `gorm:"type:decimal(15,2);default:0" json:"monthly_expenses,omitempty"`
+	MonthlySavings  Money  `gorm:"type:decimal(15,2);default:0" json:"monthly_savings,omitempty"`
+	BaseCurrency    string `gorm:"type:varchar(3);default:'INR'" json:"base_currency"` // Currency dashboards report in
 }
 
 //
@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"investment-tracker-backend/models"
+	"log"
+	"sync"
+)
+
+// Notifier delivers goal milestone and status change events to a channel
+// such as email, push notifications or a webhook
+type Notifier interface {
+	NotifyGoalEvent(goal models.Goal, event models.GoalProgressEvent) error
+}
+
+var (
+	mu        sync.RWMutex
+	notifiers []Notifier
+)
+
+// Register adds a notifier that receives every goal event from then on
+func Register(n Notifier) {
+	mu.Lock()
+	defer mu.Unlock()
+	notifiers = append(notifiers, n)
+}
+
+// GoalEvent passes an event to every registered notifier. Failures are
+// logged so that one channel cannot hold up the others.
+func GoalEvent(goal models.Goal, event models.GoalProgressEvent) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, n := range notifiers {
+		if err := n.NotifyGoalEvent(goal, event); err != nil {
+			log.Printf("Failed to notify goal %d event: %v", goal.ID, err)
+		}
+	}
+}
+
+// LogNotifier writes goal events to the standard logger
+type LogNotifier struct{}
+
+// NotifyGoalEvent implements Notifier
+func (LogNotifier) NotifyGoalEvent(goal models.Goal, event models.GoalProgressEvent) error {
+	switch event.Type {
+	case models.GoalEventMilestone:
+		log.Printf("🎯 Goal %q (user %d) reached %d%%", goal.Name, goal.UserID, event.Milestone)
+	case models.GoalEventStatusChange:
+		log.Printf("🎯 Goal %q (user %d) moved from %s to %s", goal.Name, goal.UserID, event.FromStatus, event.ToStatus)
+	}
+	return nil
+}
+
+//
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"investment-tracker-backend/models"
+	"net/http"
+	"time"
+)
+
+// WebhookNotifier posts goal events as JSON to a URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting to url with a short timeout
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// NotifyGoalEvent implements Notifier
+func (w *WebhookNotifier) NotifyGoalEvent(goal models.Goal, event models.GoalProgressEvent) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"goal_id":   goal.ID,
+		"user_id":   goal.UserID,
+		"goal_name": goal.Name,
+		"event":     event,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.Client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+//
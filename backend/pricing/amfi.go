@@ -0,0 +1,131 @@
+package pricing
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// amfiDateLayout is the date format used in AMFI's NAVAll.txt
+const amfiDateLayout = "02-Jan-2006"
+
+// NAVRecord is one scheme line from AMFI's NAVAll.txt
+type NAVRecord struct {
+	SchemeCode       string    `json:"scheme_code"`
+	ISINGrowth       string    `json:"isin_growth"`
+	ISINReinvestment string    `json:"isin_reinvestment"`
+	SchemeName       string    `json:"scheme_name"`
+	NAV              float64   `json:"nav"`
+	Date             time.Time `json:"date"`
+}
+
+// NAVFile holds parsed NAV records keyed by scheme code and ISIN. It
+// implements PriceProvider so holdings can be revalued from it directly.
+type NAVFile struct {
+	bySchemeCode map[string]NAVRecord
+	byISIN       map[string]NAVRecord
+}
+
+// ParseAMFINAV reads the semicolon-delimited AMFI NAVAll.txt format:
+//
+//	Scheme Code;ISIN Div Payout/ ISIN Growth;ISIN Div Reinvestment;Scheme Name;Net Asset Value;Date
+//
+// Section headings (scheme categories and fund house names) and schemes
+// without a published NAV are skipped.
+func ParseAMFINAV(r io.Reader) (*NAVFile, error) {
+	file := &NAVFile{
+		bySchemeCode: make(map[string]NAVRecord),
+		byISIN:       make(map[string]NAVRecord),
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "Scheme Code") {
+			continue
+		}
+
+		fields := strings.Split(line, ";")
+		if len(fields) < 6 {
+			continue // category or fund house heading
+		}
+
+		code := strings.TrimSpace(fields[0])
+		if _, err := strconv.Atoi(code); err != nil {
+			continue
+		}
+
+		nav, err := strconv.ParseFloat(strings.TrimSpace(fields[4]), 64)
+		if err != nil {
+			continue // "N.A." for schemes without a NAV
+		}
+		date, err := time.Parse(amfiDateLayout, strings.TrimSpace(fields[5]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid NAV date %q", lineNumber, fields[5])
+		}
+
+		record := NAVRecord{
+			SchemeCode:       code,
+			ISINGrowth:       cleanISIN(fields[1]),
+			ISINReinvestment: cleanISIN(fields[2]),
+			SchemeName:       strings.TrimSpace(fields[3]),
+			NAV:              nav,
+			Date:             date,
+		}
+		file.bySchemeCode[code] = record
+		if record.ISINGrowth != "" {
+			file.byISIN[record.ISINGrowth] = record
+		}
+		if record.ISINReinvestment != "" {
+			file.byISIN[record.ISINReinvestment] = record
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(file.bySchemeCode) == 0 {
+		return nil, fmt.Errorf("no schemes found; expected AMFI NAVAll.txt format")
+	}
+	return file, nil
+}
+
+// Len returns the number of schemes with a NAV
+func (f *NAVFile) Len() int {
+	return len(f.bySchemeCode)
+}
+
+// Lookup finds a scheme by scheme code, falling back to ISIN
+func (f *NAVFile) Lookup(symbol string) (NAVRecord, bool) {
+	symbol = NormalizeSymbol(symbol)
+	if record, ok := f.bySchemeCode[symbol]; ok {
+		return record, true
+	}
+	record, ok := f.byISIN[symbol]
+	return record, ok
+}
+
+// Price returns the scheme's NAV if it was published on or before the date
+func (f *NAVFile) Price(symbol string, date time.Time) (float64, error) {
+	record, ok := f.Lookup(symbol)
+	if !ok || record.Date.After(date) {
+		return 0, ErrPriceNotFound
+	}
+	return record.NAV, nil
+}
+
+// cleanISIN normalizes an ISIN column, which AMFI fills with "-" when absent
+func cleanISIN(value string) string {
+	value = NormalizeSymbol(value)
+	if value == "-" {
+		return ""
+	}
+	return value
+}
+
+//
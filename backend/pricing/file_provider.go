@@ -0,0 +1,166 @@
+package pricing
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dateLayout is the date format used in price files
+const dateLayout = "2006-01-02"
+
+type pricePoint struct {
+	Date  time.Time
+	Price float64
+}
+
+// FileProvider serves prices from a local CSV or JSON file so revaluation
+// works offline. The file is re-read whenever it changes on disk.
+//
+// CSV rows are "symbol,date,price" (a header row is optional). JSON is an
+// array of {"symbol": "...", "date": "2024-01-15", "price": 123.45}.
+type FileProvider struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	prices  map[string][]pricePoint
+}
+
+// NewFileProvider loads prices from a .csv or .json file
+func NewFileProvider(path string) (*FileProvider, error) {
+	p := &FileProvider{path: path}
+	if err := p.refresh(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Price returns the latest price for the symbol on or before the date
+func (p *FileProvider) Price(symbol string, date time.Time) (float64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.refreshLocked(); err != nil {
+		return 0, err
+	}
+
+	points := p.prices[NormalizeSymbol(symbol)]
+	// Points are sorted by date; find the first one after the requested date
+	idx := sort.Search(len(points), func(i int) bool {
+		return points[i].Date.After(date)
+	})
+	if idx == 0 {
+		return 0, ErrPriceNotFound
+	}
+	return points[idx-1].Price, nil
+}
+
+// refresh reloads the price file if it changed since the last load
+func (p *FileProvider) refresh() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.refreshLocked()
+}
+
+func (p *FileProvider) refreshLocked() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read price file: %w", err)
+	}
+	if p.prices != nil && !info.ModTime().After(p.modTime) {
+		return nil
+	}
+
+	f, err := os.Open(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to open price file: %w", err)
+	}
+	defer f.Close()
+
+	var prices map[string][]pricePoint
+	if strings.EqualFold(filepath.Ext(p.path), ".json") {
+		prices, err = parseJSONPrices(f)
+	} else {
+		prices, err = parseCSVPrices(f)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse price file %s: %w", p.path, err)
+	}
+
+	for symbol := range prices {
+		points := prices[symbol]
+		sort.Slice(points, func(a, b int) bool {
+			return points[a].Date.Before(points[b].Date)
+		})
+	}
+
+	p.prices = prices
+	p.modTime = info.ModTime()
+	return nil
+}
+
+// parseCSVPrices reads "symbol,date,price" rows
+func parseCSVPrices(r io.Reader) (map[string][]pricePoint, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string][]pricePoint)
+	for i, record := range records {
+		if len(record) < 3 {
+			return nil, fmt.Errorf("line %d: expected symbol,date,price", i+1)
+		}
+		date, err := time.Parse(dateLayout, strings.TrimSpace(record[1]))
+		if err != nil {
+			if i == 0 {
+				continue // header row
+			}
+			return nil, fmt.Errorf("line %d: invalid date %q", i+1, record[1])
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price %q", i+1, record[2])
+		}
+		symbol := NormalizeSymbol(record[0])
+		prices[symbol] = append(prices[symbol], pricePoint{Date: date, Price: price})
+	}
+	return prices, nil
+}
+
+// parseJSONPrices reads an array of {"symbol", "date", "price"} objects
+func parseJSONPrices(r io.Reader) (map[string][]pricePoint, error) {
+	var rows []struct {
+		Symbol string  `json:"symbol"`
+		Date   string  `json:"date"`
+		Price  float64 `json:"price"`
+	}
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string][]pricePoint)
+	for i, row := range rows {
+		date, err := time.Parse(dateLayout, row.Date)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: invalid date %q", i+1, row.Date)
+		}
+		symbol := NormalizeSymbol(row.Symbol)
+		prices[symbol] = append(prices[symbol], pricePoint{Date: date, Price: row.Price})
+	}
+	return prices, nil
+}
+
+//
@@ -0,0 +1,23 @@
+package pricing
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrPriceNotFound is returned when a provider has no price for a symbol on or before a date
+var ErrPriceNotFound = errors.New("price not found")
+
+// PriceProvider looks up the market price of a symbol (ticker, ISIN or
+// scheme code) as of a date
+type PriceProvider interface {
+	Price(symbol string, date time.Time) (float64, error)
+}
+
+// NormalizeSymbol makes symbol lookups case and whitespace insensitive
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+//
@@ -0,0 +1,117 @@
+package reports
+
+import (
+	"investment-tracker-backend/models"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Rebalancing actions
+const (
+	ActionBuy  = "Buy"
+	ActionSell = "Sell"
+	ActionHold = "Hold"
+)
+
+// AllocationRow compares an asset class's share of the portfolio with its target
+type AllocationRow struct {
+	AssetClass     string       `json:"asset_class"`
+	CurrentValue   models.Money `json:"current_value"`
+	CurrentPercent float64      `json:"current_percent"`
+	TargetPercent  float64      `json:"target_percent"`
+	Drift          float64      `json:"drift"` // Percentage points above (+) or below (-) target
+	WithinBand     bool         `json:"within_band"`
+	Action         string       `json:"action"`
+	Amount         models.Money `json:"amount"` // How much to buy or sell to get back to target
+}
+
+// AllocationReport is the portfolio's asset allocation with rebalancing suggestions
+type AllocationReport struct {
+	Currency       string            `json:"currency"` // Currency the values are in
+	TotalValue     models.Money      `json:"total_value"`
+	Tolerance      float64           `json:"tolerance"` // Allowed drift in percentage points
+	HasTargets     bool              `json:"has_targets"`
+	NeedsRebalance bool              `json:"needs_rebalance"`
+	Rows           []AllocationRow   `json:"rows"`
+	TypeMapping    map[string]string `json:"type_mapping"`               // Investment type to asset class, comma separated when holdings of the type fall in several
+	MissingFXRates []string          `json:"missing_fx_rates,omitempty"` // Currency pairs of holdings left out for lack of a rate
+}
+
+// BuildAllocation groups current values by asset class and compares them with
+// the target percentages. When any class drifts outside the tolerance band,
+// every class gets the buy or sell amount that brings it back to target.
+// Current values must already be in one currency.
+func BuildAllocation(investments []models.Investment, targets map[string]float64, tolerance float64) AllocationReport {
+	report := AllocationReport{
+		Tolerance:   tolerance,
+		HasTargets:  len(targets) > 0,
+		Rows:        []AllocationRow{},
+		TypeMapping: map[string]string{},
+	}
+
+	values := make(map[string]models.Money)
+	typeClasses := make(map[string]map[string]bool)
+	for _, inv := range investments {
+		class := inv.AssetClass()
+		values[class] += inv.CurrentValue
+		report.TotalValue += inv.CurrentValue
+		if typeClasses[inv.Type] == nil {
+			typeClasses[inv.Type] = make(map[string]bool)
+		}
+		typeClasses[inv.Type][class] = true
+	}
+	for investmentType, classes := range typeClasses {
+		var names []string
+		for _, class := range models.AssetClasses {
+			if classes[class] {
+				names = append(names, class)
+			}
+		}
+		report.TypeMapping[investmentType] = strings.Join(names, ", ")
+	}
+
+	for _, class := range models.AssetClasses {
+		value, held := values[class]
+		target, targeted := targets[class]
+		if !held && !targeted {
+			continue
+		}
+
+		row := AllocationRow{
+			AssetClass:    class,
+			CurrentValue:  value,
+			TargetPercent: target,
+			Action:        ActionHold,
+		}
+		if report.TotalValue > 0 {
+			row.CurrentPercent = models.Round2(value.Ratio(report.TotalValue) * 100)
+		}
+		row.Drift = models.Round2(row.CurrentPercent - target)
+		row.WithinBand = !report.HasTargets || math.Abs(row.Drift) <= tolerance
+		if !row.WithinBand {
+			report.NeedsRebalance = true
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	if report.NeedsRebalance {
+		for i := range report.Rows {
+			row := &report.Rows[i]
+			delta := report.TotalValue.Mul(row.TargetPercent/100) - row.CurrentValue
+			switch {
+			case delta >= models.NewMoney(1):
+				row.Action, row.Amount = ActionBuy, delta
+			case delta <= models.NewMoney(-1):
+				row.Action, row.Amount = ActionSell, -delta
+			}
+		}
+	}
+
+	sort.SliceStable(report.Rows, func(a, b int) bool {
+		return report.Rows[a].CurrentValue > report.Rows[b].CurrentValue
+	})
+	return report
+}
+
+//
@@ -0,0 +1,255 @@
+package reports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"investment-tracker-backend/models"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Gain terms and kinds
+const (
+	TermShort      = "Short Term"
+	TermLong       = "Long Term"
+	KindRealized   = "Realized"
+	KindUnrealized = "Unrealized"
+)
+
+// GainEntry is the gain on one lot, either sold (realized) or still held (unrealized)
+type GainEntry struct {
+	InvestmentID uint         `json:"investment_id"`
+	Name         string       `json:"name"`
+	Type         string       `json:"type"`
+	AssetClass   string       `json:"asset_class"`
+	Kind         string       `json:"kind"`
+	Term         string       `json:"term"`
+	AcquiredOn   time.Time    `json:"acquired_on"`
+	SoldOn       *time.Time   `json:"sold_on,omitempty"`
+	HoldingDays  int          `json:"holding_days"`
+	Units        float64      `json:"units"`
+	Cost         models.Money `json:"cost"`
+	Value        models.Money `json:"value"` // Sale proceeds, or current value when unrealized
+	Gain         models.Money `json:"gain"`
+}
+
+// GainTotals adds up gains by term
+type GainTotals struct {
+	ShortTermRealized   models.Money `json:"short_term_realized"`
+	LongTermRealized    models.Money `json:"long_term_realized"`
+	ShortTermUnrealized models.Money `json:"short_term_unrealized"`
+	LongTermUnrealized  models.Money `json:"long_term_unrealized"`
+}
+
+// CapitalGainsReport lists gains for a financial year
+type CapitalGainsReport struct {
+	FinancialYear string                `json:"financial_year"`
+	From          time.Time             `json:"from"`
+	To            time.Time             `json:"to"`
+	AsOf          time.Time             `json:"as_of"`
+	Entries       []GainEntry           `json:"entries"`
+	Totals        GainTotals            `json:"totals"`
+	ByAssetClass  map[string]GainTotals `json:"by_asset_class"`
+}
+
+// lot is a parcel of units bought on one date
+type lot struct {
+	date  time.Time
+	units float64
+	cost  models.Money
+}
+
+// ParseFinancialYear turns "2025-26" into 1 April 2025 to 31 March 2026
+func ParseFinancialYear(fy string) (time.Time, time.Time, error) {
+	var start, end int
+	if _, err := fmt.Sscanf(fy, "%4d-%2d", &start, &end); err != nil || (start+1)%100 != end {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid financial year %q, expected format 2025-26", fy)
+	}
+	from := time.Date(start, time.April, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(start+1, time.March, 31, 23, 59, 59, 0, time.UTC)
+	return from, to, nil
+}
+
+// CurrentFinancialYear returns the financial year (April to March) containing t
+func CurrentFinancialYear(t time.Time) string {
+	start := t.Year()
+	if t.Month() < time.April {
+		start--
+	}
+	return fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
+
+// BuildCapitalGains matches sells in [from, to] against buy lots first-in
+// first-out and lists the gains on lots still held as of asOf. Investments
+// without a ledger are treated as one lot bought on the purchase date.
+func BuildCapitalGains(fy string, from, to, asOf time.Time, investments []models.Investment,
+	ledgers map[uint][]models.InvestmentTransaction, rules HoldingRules) CapitalGainsReport {
+	report := CapitalGainsReport{
+		FinancialYear: fy,
+		From:          from,
+		To:            to,
+		AsOf:          asOf,
+		Entries:       []GainEntry{},
+		ByAssetClass:  map[string]GainTotals{},
+	}
+
+	for _, inv := range investments {
+		months := rules.LongTermMonths(inv)
+		class := inv.AssetClass()
+		entry := func(kind string, l lot, soldOn time.Time, value models.Money) GainEntry {
+			e := GainEntry{
+				InvestmentID: inv.ID,
+				Name:         inv.Name,
+				Type:         inv.Type,
+				AssetClass:   class,
+				Kind:         kind,
+				Term:         TermShort,
+				AcquiredOn:   l.date,
+				HoldingDays:  int(soldOn.Sub(l.date).Hours() / 24),
+				Units:        models.Round2(l.units),
+				Cost:         l.cost,
+				Value:        value,
+				Gain:         value - l.cost,
+			}
+			if soldOn.After(l.date.AddDate(0, months, 0)) {
+				e.Term = TermLong
+			}
+			if kind == KindRealized {
+				sold := soldOn
+				e.SoldOn = &sold
+			}
+			return e
+		}
+
+		ledger := ledgers[inv.ID]
+		if len(ledger) == 0 {
+			if inv.Invested > 0 && !inv.PurchaseDate.After(asOf) {
+				held := lot{date: inv.PurchaseDate, units: inv.Units, cost: inv.Invested}
+				report.add(entry(KindUnrealized, held, asOf, inv.CurrentValue))
+			}
+			continue
+		}
+
+		lots := replayLots(ledger, func(l lot, soldOn time.Time, proceeds models.Money) {
+			if !soldOn.Before(from) && !soldOn.After(to) {
+				report.add(entry(KindRealized, l, soldOn, proceeds))
+			}
+		})
+
+		// Value the lots still held at the investment's current price per unit
+		var heldUnits float64
+		for _, l := range lots {
+			heldUnits += l.units
+		}
+		for _, l := range lots {
+			if l.units <= 0 || heldUnits <= 0 {
+				continue
+			}
+			report.add(entry(KindUnrealized, l, asOf, inv.CurrentValue.Mul(l.units/heldUnits)))
+		}
+	}
+
+	sort.SliceStable(report.Entries, func(a, b int) bool {
+		if report.Entries[a].Kind != report.Entries[b].Kind {
+			return report.Entries[a].Kind == KindRealized
+		}
+		return report.Entries[a].AcquiredOn.Before(report.Entries[b].AcquiredOn)
+	})
+	return report
+}
+
+// replayLots walks the ledger in date order, calling sold for every part of
+// a lot consumed by a sell, and returns the lots still held
+func replayLots(ledger []models.InvestmentTransaction, sold func(l lot, soldOn time.Time, proceeds models.Money)) []lot {
+	sorted := make([]models.InvestmentTransaction, len(ledger))
+	copy(sorted, ledger)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].Date.Before(sorted[b].Date)
+	})
+
+	var lots []lot
+	for _, t := range sorted {
+		switch t.Type {
+		case models.TransactionBuy:
+			if t.Units > 0 {
+				lots = append(lots, lot{date: t.Date, units: t.Units, cost: t.Amount})
+			}
+		case models.TransactionSplit:
+			for i := range lots {
+				lots[i].units *= t.Units
+			}
+		case models.TransactionSell:
+			remaining := t.Units
+			for len(lots) > 0 && remaining > 1e-9 {
+				head := &lots[0]
+				take := math.Min(head.units, remaining)
+				part := lot{date: head.date, units: take, cost: head.cost.Mul(take / head.units)}
+				sold(part, t.Date, t.Amount.Mul(take/t.Units))
+
+				head.units -= take
+				head.cost -= part.cost
+				remaining -= take
+				if head.units <= 1e-9 {
+					lots = lots[1:]
+				}
+			}
+		}
+	}
+	return lots
+}
+
+// add records an entry and adds it to the totals
+func (r *CapitalGainsReport) add(e GainEntry) {
+	r.Entries = append(r.Entries, e)
+
+	classTotals := r.ByAssetClass[e.AssetClass]
+	for _, totals := range []*GainTotals{&r.Totals, &classTotals} {
+		switch {
+		case e.Kind == KindRealized && e.Term == TermShort:
+			totals.ShortTermRealized += e.Gain
+		case e.Kind == KindRealized:
+			totals.LongTermRealized += e.Gain
+		case e.Term == TermShort:
+			totals.ShortTermUnrealized += e.Gain
+		default:
+			totals.LongTermUnrealized += e.Gain
+		}
+	}
+	r.ByAssetClass[e.AssetClass] = classTotals
+}
+
+// WriteCSV writes the report entries as CSV
+func (r CapitalGainsReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"investment_id", "name", "type", "asset_class", "kind", "term", "acquired_on", "sold_on", "holding_days", "units", "cost", "value", "gain"})
+
+	for _, e := range r.Entries {
+		soldOn := ""
+		if e.SoldOn != nil {
+			soldOn = e.SoldOn.Format("2006-01-02")
+		}
+		writer.Write([]string{
+			strconv.FormatUint(uint64(e.InvestmentID), 10),
+			e.Name,
+			e.Type,
+			e.AssetClass,
+			e.Kind,
+			e.Term,
+			e.AcquiredOn.Format("2006-01-02"),
+			soldOn,
+			strconv.Itoa(e.HoldingDays),
+			strconv.FormatFloat(e.Units, 'f', -1, 64),
+			e.Cost.String(),
+			e.Value.String(),
+			e.Gain.String(),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+//
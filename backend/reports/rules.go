@@ -0,0 +1,71 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"investment-tracker-backend/models"
+	"os"
+	"strings"
+)
+
+// HoldingRules decide after how many months a holding turns long-term.
+// A rule for the investment's type wins over one for its asset class, which
+// wins over the default.
+type HoldingRules struct {
+	ByType        map[string]int `json:"by_type"`
+	ByAssetClass  map[string]int `json:"by_asset_class"`
+	DefaultMonths int            `json:"default_months"`
+}
+
+// DefaultHoldingRules follows Indian capital gains rules: listed equity is
+// long-term after 12 months, gold and real estate after 24, and debt after 36
+func DefaultHoldingRules() HoldingRules {
+	return HoldingRules{
+		ByType: map[string]int{},
+		ByAssetClass: map[string]int{
+			models.AssetClassEquity:     12,
+			models.AssetClassGold:       24,
+			models.AssetClassRealEstate: 24,
+			models.AssetClassDebt:       36,
+		},
+		DefaultMonths: 36,
+	}
+}
+
+// LoadHoldingRules reads rules from a JSON file on top of the defaults
+func LoadHoldingRules(path string) (HoldingRules, error) {
+	rules := DefaultHoldingRules()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, fmt.Errorf("failed to read holding rules: %w", err)
+	}
+
+	var overrides HoldingRules
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return rules, fmt.Errorf("invalid holding rules: %w", err)
+	}
+	for investmentType, months := range overrides.ByType {
+		rules.ByType[strings.ToLower(investmentType)] = months
+	}
+	for class, months := range overrides.ByAssetClass {
+		rules.ByAssetClass[class] = months
+	}
+	if overrides.DefaultMonths > 0 {
+		rules.DefaultMonths = overrides.DefaultMonths
+	}
+	return rules, nil
+}
+
+// LongTermMonths returns the holding period after which the investment is long-term
+func (r HoldingRules) LongTermMonths(inv models.Investment) int {
+	if months, ok := r.ByType[strings.ToLower(strings.TrimSpace(inv.Type))]; ok {
+		return months
+	}
+	if months, ok := r.ByAssetClass[inv.AssetClass()]; ok {
+		return months
+	}
+	return r.DefaultMonths
+}
+
+//
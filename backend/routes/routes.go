@@ -31,20 +31,80 @@ func SetupRoutes(router *gin.Engine) {
 			investments := protected.Group("/investments")
 			{
 				investments.GET("", controllers.GetInvestments)
+				investments.GET("/maturing", controllers.GetMaturingDeposits)
+				investments.GET("/benchmark", controllers.GetBenchmarkComparison)
 				investments.GET("/:id", controllers.GetInvestment)
 				investments.POST("", controllers.CreateInvestment)
+				investments.POST("/import", controllers.ImportInvestments)
 				investments.PUT("/:id", controllers.UpdateInvestment)
 				investments.DELETE("/:id", controllers.DeleteInvestment)
 				investments.POST("/:id/link-goal", controllers.LinkInvestmentToGoal)
 				investments.POST("/:id/unlink-goal", controllers.UnlinkInvestmentFromGoal)
 				investments.GET("/by-goal/:goal_id", controllers.GetInvestmentsByGoal)
+				investments.GET("/:id/transactions", controllers.GetInvestmentTransactions)
+				investments.POST("/:id/transactions", controllers.CreateInvestmentTransaction)
+				investments.DELETE("/:id/transactions/:transaction_id", controllers.DeleteInvestmentTransaction)
+				investments.GET("/:id/income", controllers.GetIncomeEvents)
+				investments.POST("/:id/income", controllers.CreateIncomeEvent)
+				investments.DELETE("/:id/income/:income_id", controllers.DeleteIncomeEvent)
+			}
+
+			// Price routes (admin only, NAV imports revalue every user's holdings)
+			prices := protected.Group("/prices")
+			prices.Use(middleware.AdminMiddleware())
+			{
+				prices.POST("/amfi-nav", controllers.ImportAMFINAV)
+			}
+
+			// Benchmark routes (importing prices is admin only)
+			benchmarks := protected.Group("/benchmarks")
+			{
+				benchmarks.GET("", controllers.GetBenchmarks)
+				benchmarks.POST("/import", middleware.AdminMiddleware(), controllers.ImportBenchmark)
+			}
+
+			// FX rate routes (importing rates is admin only)
+			fxRates := protected.Group("/fx-rates")
+			{
+				fxRates.GET("", controllers.GetFXRates)
+				fxRates.GET("/convert", controllers.ConvertCurrency)
+				fxRates.POST("/import", middleware.AdminMiddleware(), controllers.ImportFXRates)
+			}
+
+			// Corporate action routes (admin only, they adjust every user's holdings)
+			corporateActions := protected.Group("/corporate-actions")
+			corporateActions.Use(middleware.AdminMiddleware())
+			{
+				corporateActions.GET("", controllers.GetCorporateActions)
+				corporateActions.POST("", controllers.CreateCorporateAction)
+				corporateActions.POST("/:id/apply", controllers.ApplyCorporateAction)
+				corporateActions.POST("/:id/reverse", controllers.ReverseCorporateAction)
+				corporateActions.GET("/:id/audit", controllers.GetCorporateActionAudit)
+			}
+
+			// SIP routes
+			sips := protected.Group("/sips")
+			{
+				sips.GET("", controllers.GetSIPs)
+				sips.GET("/upcoming", controllers.GetUpcomingSIPs)
+				sips.POST("", controllers.CreateSIP)
+				sips.PUT("/:id", controllers.UpdateSIP)
+				sips.DELETE("/:id", controllers.DeleteSIP)
 			}
 
 			// Goal routes
 			goals := protected.Group("/goals")
 			{
 				goals.GET("", controllers.GetGoals)
+				goals.GET("/templates", controllers.GetGoalTemplates)
+				goals.GET("/plan", controllers.GetPlannedContributions)
+				goals.POST("/plan", controllers.PlanGoalContributions)
 				goals.GET("/:id", controllers.GetGoal)
+				goals.GET("/:id/simulation", controllers.GetGoalSimulation)
+				goals.GET("/:id/history", controllers.GetGoalHistory)
+				goals.GET("/:id/contributions", controllers.GetGoalContributions)
+				goals.POST("/:id/contributions", controllers.CreateGoalContribution)
+				goals.DELETE("/:id/contributions/:contribution_id", controllers.DeleteGoalContribution)
 				goals.POST("", controllers.CreateGoal)
 				goals.PUT("/:id", controllers.UpdateGoal)
 				goals.DELETE("/:id", controllers.DeleteGoal)
@@ -77,6 +137,36 @@ func SetupRoutes(router *gin.Engine) {
 				users.PUT("/:id/financials", controllers.UpdateUserFinancials)
 			}
 
+			// Portfolio routes
+			portfolio := protected.Group("/portfolio")
+			{
+				portfolio.GET("/allocation", controllers.GetAllocation)
+				portfolio.GET("/allocation/targets", controllers.GetAllocationTargets)
+				portfolio.PUT("/allocation/targets", controllers.SetAllocationTargets)
+			}
+
+			// Status rule routes
+			statusRules := protected.Group("/status-rules")
+			{
+				statusRules.GET("", controllers.GetStatusRules)
+				statusRules.POST("", controllers.CreateStatusRule)
+				statusRules.PUT("/:id", controllers.UpdateStatusRule)
+				statusRules.DELETE("/:id", controllers.DeleteStatusRule)
+			}
+
+			// Report routes
+			reports := protected.Group("/reports")
+			{
+				reports.GET("/capital-gains", controllers.GetCapitalGainsReport)
+			}
+
+			// Net-worth history routes
+			history := protected.Group("/history")
+			{
+				history.GET("/networth", controllers.GetNetWorthHistory)
+				history.POST("/networth/backfill", controllers.BackfillNetWorthHistory)
+			}
+
 			// Dashboard route
 			protected.GET("/dashboard", controllers.GetDashboard)
 		}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/pricing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NAVImportSummary reports the outcome of an AMFI NAV import
+type NAVImportSummary struct {
+	SchemesInFile    int      `json:"schemes_in_file"`
+	HoldingsChecked  int      `json:"holdings_checked"`
+	HoldingsRevalued int      `json:"holdings_revalued"`
+	WithoutUnits     int      `json:"holdings_without_units"` // Matched, but no units to value
+	UnmatchedSchemes []string `json:"unmatched_schemes"`      // Scheme codes held but missing from the file
+	GoalsRefreshed   int      `json:"goals_refreshed"`
+}
+
+// ImportAMFINAV revalues every mutual fund holding, across all users, whose
+// symbol matches a scheme code (or ISIN) in the NAV file
+func ImportAMFINAV(db *gorm.DB, navs *pricing.NAVFile) (NAVImportSummary, error) {
+	summary := NAVImportSummary{
+		SchemesInFile:    navs.Len(),
+		UnmatchedSchemes: []string{},
+	}
+
+	var holdings []models.Investment
+	if err := db.Where("symbol <> '' AND LOWER(type) LIKE ?", "%mutual fund%").Find(&holdings).Error; err != nil {
+		return summary, err
+	}
+	summary.HoldingsChecked = len(holdings)
+
+	var priced []models.Investment
+	for _, inv := range holdings {
+		if inv.Units <= 0 {
+			if _, ok := navs.Lookup(inv.Symbol); ok {
+				summary.WithoutUnits++
+				continue
+			}
+		}
+		priced = append(priced, inv)
+	}
+
+	// NAVs are published for the previous business day, so accept any date up to now
+	result, err := revalueAll(db, priced, navs, time.Now())
+	summary.HoldingsRevalued = result.Revalued
+	summary.UnmatchedSchemes = result.Missing
+	summary.GoalsRefreshed = result.Goals
+	return summary, err
+}
+
+//
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"investment-tracker-backend/finance"
+	"investment-tracker-backend/models"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BenchmarkComparison sets a holding's return beside what the same cash flows
+// would have earned in a benchmark
+type BenchmarkComparison struct {
+	InvestmentID    uint         `json:"investment_id,omitempty"`
+	Name            string       `json:"name,omitempty"`
+	Benchmark       string       `json:"benchmark"`
+	Covered         bool         `json:"covered"` // False when the benchmark history does not reach back to the first cash flow
+	PaidIn          models.Money `json:"paid_in"` // Money put in
+	Value           models.Money `json:"value"`   // Current value plus money taken out
+	Return          float64      `json:"return"`  // Percentage
+	XIRR            *float64     `json:"xirr"`    // Percentage
+	BenchmarkValue  models.Money `json:"benchmark_value"`
+	BenchmarkReturn float64      `json:"benchmark_return"`
+	BenchmarkXIRR   *float64     `json:"benchmark_xirr"`
+	Alpha           *float64     `json:"alpha"` // XIRR minus benchmark XIRR, in percentage points
+}
+
+// CompareWithBenchmark compares cash flows (without the closing value) and the
+// holding's current value against the same flows invested in the benchmark
+func CompareWithBenchmark(flows []finance.CashFlow, value models.Money, benchmark string, series finance.PriceSeries, asOf time.Time) BenchmarkComparison {
+	comparison := BenchmarkComparison{Benchmark: benchmark}
+
+	paidIn, takenOut := 0.0, 0.0
+	for _, f := range flows {
+		if f.Amount < 0 {
+			paidIn -= f.Amount
+		} else {
+			takenOut += f.Amount
+		}
+	}
+	comparison.PaidIn = models.NewMoney(paidIn)
+	comparison.Value = value + models.NewMoney(takenOut)
+	if comparison.PaidIn > 0 {
+		comparison.Return = models.Round2((comparison.Value - comparison.PaidIn).Ratio(comparison.PaidIn) * 100)
+	}
+	comparison.XIRR, _ = models.AnnualizedReturns(withClosingValue(flows, value.Float64(), asOf), asOf)
+
+	benchmarkValue, ok := finance.ReplicateFlows(flows, series, asOf)
+	if !ok {
+		return comparison
+	}
+	comparison.Covered = true
+	comparison.BenchmarkValue = models.NewMoney(benchmarkValue + takenOut)
+	if comparison.PaidIn > 0 {
+		comparison.BenchmarkReturn = models.Round2((comparison.BenchmarkValue - comparison.PaidIn).Ratio(comparison.PaidIn) * 100)
+	}
+	comparison.BenchmarkXIRR, _ = models.AnnualizedReturns(withClosingValue(flows, benchmarkValue, asOf), asOf)
+
+	if comparison.XIRR != nil && comparison.BenchmarkXIRR != nil {
+		alpha := models.Round2(*comparison.XIRR - *comparison.BenchmarkXIRR)
+		comparison.Alpha = &alpha
+	}
+	return comparison
+}
+
+// withClosingValue appends the closing value of a holding to its cash flows
+func withClosingValue(flows []finance.CashFlow, value float64, asOf time.Time) []finance.CashFlow {
+	closed := append([]finance.CashFlow{}, flows...)
+	if value > 0 {
+		closed = append(closed, finance.CashFlow{Date: asOf, Amount: value})
+	}
+	return closed
+}
+
+// LoadBenchmarkSeries finds a benchmark by name and loads its price history
+func LoadBenchmarkSeries(db *gorm.DB, name string) (*models.Benchmark, finance.PriceSeries, error) {
+	var benchmark models.Benchmark
+	if err := db.Where("UPPER(name) = ?", strings.ToUpper(strings.TrimSpace(name))).First(&benchmark).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var prices []models.BenchmarkPrice
+	if err := db.Where("benchmark_id = ?", benchmark.ID).Order("date ASC").Find(&prices).Error; err != nil {
+		return nil, nil, err
+	}
+
+	series := make(finance.PriceSeries, len(prices))
+	for i, p := range prices {
+		series[i] = finance.PricePoint{Date: p.Date, Close: p.Close}
+	}
+	return &benchmark, series, nil
+}
+
+// ImportBenchmarkCSV loads "date,close" rows (a header row is optional) into
+// the named benchmark, creating it if needed and overwriting existing days.
+// It returns the number of prices loaded.
+func ImportBenchmarkCSV(db *gorm.DB, name string, r io.Reader) (int, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" {
+		return 0, fmt.Errorf("benchmark name is required")
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	var prices []models.BenchmarkPrice
+	for i, record := range records {
+		if len(record) < 2 {
+			return 0, fmt.Errorf("line %d: expected date,close", i+1)
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff")))
+		if err != nil {
+			if i == 0 {
+				continue // header row
+			}
+			return 0, fmt.Errorf("line %d: invalid date %q", i+1, record[0])
+		}
+		closePrice, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(record[1]), ",", ""), 64)
+		if err != nil || closePrice <= 0 {
+			return 0, fmt.Errorf("line %d: invalid close %q", i+1, record[1])
+		}
+		prices = append(prices, models.BenchmarkPrice{Date: date, Close: closePrice})
+	}
+	if len(prices) == 0 {
+		return 0, fmt.Errorf("no prices found")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		benchmark := models.Benchmark{Name: name}
+		if err := tx.Where("name = ?", name).FirstOrCreate(&benchmark).Error; err != nil {
+			return err
+		}
+		for i := range prices {
+			prices[i].BenchmarkID = benchmark.ID
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "benchmark_id"}, {Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"close"}),
+		}).CreateInBatches(prices, 500).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(prices), nil
+}
+
+// LoadBenchmarkDir imports every .csv file in a directory, naming each
+// benchmark after its file (nifty50.csv becomes NIFTY50)
+func LoadBenchmarkDir(db *gorm.DB, dir string) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		_, err = ImportBenchmarkCSV(db, name, f)
+		f.Close()
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+	}
+	return len(paths), nil
+}
+
+//
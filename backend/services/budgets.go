@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"investment-tracker-backend/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SaveBudgetCategories replaces a budget's category limits
+func SaveBudgetCategories(db *gorm.DB, budget *models.Budget, categories []models.BudgetCategory) error {
+	if err := models.ValidateBudgetCategories(categories); err != nil {
+		return err
+	}
+
+	for i := range categories {
+		categories[i].ID = 0
+		categories[i].UserID = budget.UserID
+		categories[i].BudgetID = budget.ID
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("budget_id = ?", budget.ID).Delete(&models.BudgetCategory{}).Error; err != nil {
+			return err
+		}
+		if len(categories) == 0 {
+			return nil
+		}
+		return tx.Create(&categories).Error
+	})
+	if err != nil {
+		return err
+	}
+	budget.Categories = categories
+	return nil
+}
+
+// BudgetExpensesTotal adds up the expenses recorded against a budget,
+// converted to the budget's currency at the rate of their date. Expenses
+// without an exchange rate are left out and listed by fx.Missing.
+func BudgetExpensesTotal(db *gorm.DB, fx *FXConverter, budget models.Budget) (models.Money, error) {
+	var expenses []models.Expense
+	if err := db.Where("budget_id = ?", budget.ID).Find(&expenses).Error; err != nil {
+		return 0, err
+	}
+
+	var total models.Money
+	for _, expense := range expenses {
+		amount, err := fx.Convert(expense.Amount, expense.Currency, budget.Currency, expense.Date)
+		if errors.Is(err, ErrNoFXRate) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		total += amount
+	}
+	return total, nil
+}
+
+// LoadBudgetBreakdown loads a budget's category limits with what was spent in
+// each during the budget's month, from expenses of the same category
+// (ignoring case) converted to the budget's currency at the rate of their date.
+// Expenses without an exchange rate are left out and their currency pairs
+// listed on the budget.
+func LoadBudgetBreakdown(db *gorm.DB, budget *models.Budget) error {
+	var categories []models.BudgetCategory
+	if err := db.Where("budget_id = ?", budget.ID).Order("category ASC").Find(&categories).Error; err != nil {
+		return err
+	}
+	budget.Categories = categories
+	if len(categories) == 0 {
+		return nil
+	}
+
+	from, to, err := budget.MonthRange()
+	if err != nil {
+		return err
+	}
+	var expenses []models.Expense
+	if err := db.Where("user_id = ? AND date >= ? AND date < ?", budget.UserID, from, to).Find(&expenses).Error; err != nil {
+		return err
+	}
+
+	// Only expenses in a budgeted category need converting
+	spent := make(map[string]models.Money)
+	for _, category := range categories {
+		spent[strings.ToLower(category.Category)] = 0
+	}
+
+	fx := NewFXConverter(db)
+	for _, expense := range expenses {
+		key := strings.ToLower(strings.TrimSpace(expense.Category))
+		if _, ok := spent[key]; !ok {
+			continue
+		}
+		amount, err := fx.Convert(expense.Amount, expense.Currency, budget.Currency, expense.Date)
+		if errors.Is(err, ErrNoFXRate) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		spent[key] += amount
+	}
+	for _, pair := range fx.Missing() {
+		budget.MissingFXRates = appendPair(budget.MissingFXRates, pair)
+	}
+
+	for i := range budget.Categories {
+		category := &budget.Categories[i]
+		category.Spent = spent[strings.ToLower(category.Category)]
+		category.CalculateRemaining()
+	}
+	return nil
+}
+
+//
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"investment-tracker-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ApplyCorporateAction adjusts every holding of the action's symbol, across
+// all users, in one database transaction. Units change by the action's
+// multiplier while the invested amount and market value stay the same.
+// Holdings with a ledger get a Split transaction. Every change is audited so
+// the action can be reversed. It returns the number of holdings adjusted.
+func ApplyCorporateAction(db *gorm.DB, action *models.CorporateAction) (int, error) {
+	if action.Status == models.CorporateActionApplied {
+		return 0, errors.New("corporate action has already been applied")
+	}
+
+	// Audits written from now on belong to this application
+	appliedAt := time.Now()
+	count := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var holdings []models.Investment
+		if err := tx.Where("UPPER(symbol) = UPPER(?)", action.Symbol).Find(&holdings).Error; err != nil {
+			return err
+		}
+
+		multiplier := action.Multiplier()
+		for i := range holdings {
+			inv := &holdings[i]
+			audit := models.CorporateActionAudit{
+				CorporateActionID: action.ID,
+				InvestmentID:      inv.ID,
+				UserID:            inv.UserID,
+				Operation:         "Apply",
+				UnitsBefore:       inv.Units,
+				SymbolBefore:      inv.Symbol,
+				InvestedBefore:    inv.Invested,
+			}
+
+			var ledger []models.InvestmentTransaction
+			if multiplier != 1 {
+				if err := tx.Where("investment_id = ?", inv.ID).Find(&ledger).Error; err != nil {
+					return err
+				}
+				if len(ledger) > 0 {
+					split := models.InvestmentTransaction{
+						UserID:       inv.UserID,
+						InvestmentID: inv.ID,
+						Type:         models.TransactionSplit,
+						Units:        multiplier,
+						Date:         action.ExDate,
+						Notes:        fmt.Sprintf("Corporate action #%d: %s %g:%g", action.ID, action.Type, action.RatioFrom, action.RatioTo),
+					}
+					if err := tx.Create(&split).Error; err != nil {
+						return err
+					}
+					ledger = append(ledger, split)
+					audit.TransactionID = &split.ID
+				}
+			}
+			applyToHolding(inv, action, ledger)
+
+			if err := tx.Save(inv).Error; err != nil {
+				return err
+			}
+
+			audit.UnitsAfter = inv.Units
+			audit.SymbolAfter = inv.Symbol
+			audit.InvestedAfter = inv.Invested
+			if err := tx.Create(&audit).Error; err != nil {
+				return err
+			}
+			count++
+		}
+
+		action.Status = models.CorporateActionApplied
+		action.AppliedAt = &appliedAt
+		action.ReversedAt = nil
+		return tx.Save(action).Error
+	})
+	return count, err
+}
+
+// ReverseCorporateAction undoes an applied action using its audit entries
+func ReverseCorporateAction(db *gorm.DB, action *models.CorporateAction) (int, error) {
+	if action.Status != models.CorporateActionApplied {
+		return 0, errors.New("only applied corporate actions can be reversed")
+	}
+
+	count := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var audits []models.CorporateActionAudit
+		if err := tx.Where("corporate_action_id = ? AND operation = ? AND created_at >= ?", action.ID, "Apply", *action.AppliedAt).
+			Find(&audits).Error; err != nil {
+			return err
+		}
+
+		multiplier := action.Multiplier()
+		for _, applied := range audits {
+			var inv models.Investment
+			if err := tx.First(&inv, applied.InvestmentID).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+				continue // holding deleted since
+			} else if err != nil {
+				return err
+			}
+
+			audit := models.CorporateActionAudit{
+				CorporateActionID: action.ID,
+				InvestmentID:      inv.ID,
+				UserID:            inv.UserID,
+				Operation:         "Reverse",
+				UnitsBefore:       inv.Units,
+				SymbolBefore:      inv.Symbol,
+				InvestedBefore:    inv.Invested,
+			}
+
+			// Remove the split from the ledger, if it went into one, and
+			// re-derive the holding from what is left
+			if applied.TransactionID != nil {
+				if err := tx.Delete(&models.InvestmentTransaction{}, *applied.TransactionID).Error; err != nil {
+					return err
+				}
+			}
+			var ledger []models.InvestmentTransaction
+			if err := tx.Where("investment_id = ?", inv.ID).Find(&ledger).Error; err != nil {
+				return err
+			}
+			reverseOnHolding(&inv, applied, multiplier, ledger)
+
+			if err := tx.Save(&inv).Error; err != nil {
+				return err
+			}
+
+			audit.UnitsAfter = inv.Units
+			audit.SymbolAfter = inv.Symbol
+			audit.InvestedAfter = inv.Invested
+			if err := tx.Create(&audit).Error; err != nil {
+				return err
+			}
+			count++
+		}
+
+		now := time.Now()
+		action.Status = models.CorporateActionReversed
+		action.ReversedAt = &now
+		return tx.Save(action).Error
+	})
+	return count, err
+}
+
+// applyToHolding adjusts a holding for an action. ledger is the holding's
+// transactions including the action's split, or empty for a holding without
+// a ledger.
+func applyToHolding(inv *models.Investment, action *models.CorporateAction, ledger []models.InvestmentTransaction) {
+	if multiplier := action.Multiplier(); multiplier != 1 {
+		adjustUnits(inv, multiplier, ledger)
+	}
+	if action.NewSymbol != "" {
+		inv.Symbol = action.NewSymbol
+	}
+}
+
+// reverseOnHolding undoes an action recorded by applied on a holding. ledger
+// is the holding's transactions with the action's split removed.
+func reverseOnHolding(inv *models.Investment, applied models.CorporateActionAudit, multiplier float64, ledger []models.InvestmentTransaction) {
+	if multiplier != 1 {
+		adjustUnits(inv, 1/multiplier, ledger)
+	}
+	if applied.SymbolAfter != applied.SymbolBefore && inv.Symbol == applied.SymbolAfter {
+		inv.Symbol = applied.SymbolBefore
+	}
+}
+
+// adjustUnits multiplies the units held while keeping invested amount and
+// market value. Holdings with a ledger are re-derived from it instead.
+func adjustUnits(inv *models.Investment, multiplier float64, ledger []models.InvestmentTransaction) {
+	if len(ledger) == 0 {
+		inv.Units *= multiplier
+		return
+	}
+
+	// A split changes the units, not what the holding is worth
+	value := inv.CurrentValue
+	inv.ApplyLedger(ledger)
+	inv.CurrentValue = value
+	inv.CalculateReturns()
+}
+
+//
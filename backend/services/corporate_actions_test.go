@@ -0,0 +1,68 @@
+package services
+
+import (
+	"investment-tracker-backend/models"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestReverseSplitWithoutLedger(t *testing.T) {
+	action := &models.CorporateAction{
+		Type:      models.CorporateActionSplit,
+		Symbol:    "INFY",
+		RatioFrom: 1,
+		RatioTo:   5,
+	}
+	inv := models.Investment{Symbol: "INFY", Units: 12, Invested: models.NewMoney(6000), CurrentValue: models.NewMoney(7200)}
+
+	applied := models.CorporateActionAudit{UnitsBefore: inv.Units, SymbolBefore: inv.Symbol}
+	applyToHolding(&inv, action, nil)
+	applied.UnitsAfter, applied.SymbolAfter = inv.Units, inv.Symbol
+	if inv.Units != 60 {
+		t.Fatalf("Units after the split = %v, want 60", inv.Units)
+	}
+
+	reverseOnHolding(&inv, applied, action.Multiplier(), nil)
+	if math.Abs(inv.Units-12) > 1e-9 {
+		t.Errorf("Units after reversing = %v, want 12", inv.Units)
+	}
+	if inv.Invested != models.NewMoney(6000) || inv.CurrentValue != models.NewMoney(7200) {
+		t.Errorf("Invested = %s, CurrentValue = %s, want them unchanged", inv.Invested, inv.CurrentValue)
+	}
+}
+
+func TestReverseMergerWithLedger(t *testing.T) {
+	action := &models.CorporateAction{
+		Type:      models.CorporateActionMerger,
+		Symbol:    "OLD",
+		NewSymbol: "NEW",
+		RatioFrom: 2,
+		RatioTo:   3,
+	}
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	ledger := []models.InvestmentTransaction{
+		{ID: 1, Type: models.TransactionBuy, Units: 10, Price: 100, Amount: models.NewMoney(1000), Date: day(1)},
+	}
+	inv := models.Investment{Symbol: "OLD", CurrentValue: models.NewMoney(1500)}
+	inv.ApplyLedger(ledger)
+	inv.CurrentValue = models.NewMoney(1500)
+
+	applied := models.CorporateActionAudit{UnitsBefore: inv.Units, SymbolBefore: inv.Symbol}
+	split := models.InvestmentTransaction{ID: 2, Type: models.TransactionSplit, Units: action.Multiplier(), Date: day(2)}
+	applyToHolding(&inv, action, append(ledger, split))
+	applied.UnitsAfter, applied.SymbolAfter = inv.Units, inv.Symbol
+	if inv.Units != 15 || inv.Symbol != "NEW" {
+		t.Fatalf("after the merger Units = %v, Symbol = %s, want 15 and NEW", inv.Units, inv.Symbol)
+	}
+
+	reverseOnHolding(&inv, applied, action.Multiplier(), ledger)
+	if inv.Units != 10 || inv.Symbol != "OLD" {
+		t.Errorf("after reversing Units = %v, Symbol = %s, want 10 and OLD", inv.Units, inv.Symbol)
+	}
+	if inv.CurrentValue != models.NewMoney(1500) {
+		t.Errorf("CurrentValue = %s, want 1500.00", inv.CurrentValue)
+	}
+}
+
+//
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"investment-tracker-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccrueDeposits brings every fixed and recurring deposit's value up to asOf,
+// recalculating returns and status, and refreshes linked goals. It returns
+// the number of deposits updated.
+func AccrueDeposits(db *gorm.DB, asOf time.Time) (int, error) {
+	var deposits []models.Investment
+	if err := db.Where("interest_rate > 0").Find(&deposits).Error; err != nil {
+		return 0, err
+	}
+
+	rules := NewStatusRules(db)
+	count := 0
+	var updated []uint
+	for i := range deposits {
+		inv := &deposits[i]
+		if !inv.AccrueDeposit(asOf) {
+			continue
+		}
+		if err := rules.Apply(inv); err != nil {
+			return count, err
+		}
+
+		if err := db.Model(inv).Updates(map[string]interface{}{
+			"invested":       inv.Invested,
+			"current_value":  inv.CurrentValue,
+			"returns":        inv.Returns,
+			"status":         inv.Status,
+			"peak_value":     inv.PeakValue,
+			"maturity_date":  inv.MaturityDate,
+			"maturity_value": inv.MaturityValue,
+		}).Error; err != nil {
+			return count, err
+		}
+		count++
+		updated = append(updated, inv.ID)
+	}
+
+	if _, err := RefreshInvestmentGoals(db, updated...); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+//
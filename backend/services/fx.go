@@ -0,0 +1,214 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"investment-tracker-backend/finance"
+	"investment-tracker-backend/models"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoFXRate is returned when no exchange rate is known for a currency pair on or before a date
+var ErrNoFXRate = errors.New("no exchange rate available")
+
+// FXConverter converts amounts between currencies using the FX rate table,
+// caching each currency pair's rates after the first lookup and remembering
+// the pairs it had no rate for
+type FXConverter struct {
+	db      *gorm.DB
+	rates   map[string][]models.FXRate
+	missing map[string]bool
+}
+
+// NewFXConverter creates a converter reading rates from db
+func NewFXConverter(db *gorm.DB) *FXConverter {
+	return &FXConverter{db: db, rates: make(map[string][]models.FXRate), missing: make(map[string]bool)}
+}
+
+// Missing lists the currency pairs, as "FROM/TO", that could not be converted so far
+func (c *FXConverter) Missing() []string {
+	pairs := make([]string, 0, len(c.missing))
+	for pair := range c.missing {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// Convert converts an amount between currencies at the rate for the date
+func (c *FXConverter) Convert(amount models.Money, from, to string, date time.Time) (models.Money, error) {
+	if amount == 0 {
+		return 0, nil
+	}
+	rate, err := c.Rate(from, to, date)
+	if err != nil {
+		return 0, err
+	}
+	return amount.Mul(rate), nil
+}
+
+// Rate returns the latest rate on or before the date for converting from one
+// currency to another. Pairs without a direct rate use the inverse rate, or
+// cross through the default currency.
+func (c *FXConverter) Rate(from, to string, date time.Time) (float64, error) {
+	rate, err := c.rate(from, to, date)
+	if errors.Is(err, ErrNoFXRate) {
+		c.missing[fxPair(from, to)] = true
+	}
+	return rate, err
+}
+
+// rate looks up a rate without recording the pair as missing, so that a
+// failed cross rate is only reported for the pair asked for
+func (c *FXConverter) rate(from, to string, date time.Time) (float64, error) {
+	from, to = models.NormalizeCurrency(from), models.NormalizeCurrency(to)
+	if from == to {
+		return 1, nil
+	}
+
+	rate, err := c.pairRate(from, to, date)
+	if !errors.Is(err, ErrNoFXRate) {
+		return rate, err
+	}
+	rate, err = c.pairRate(to, from, date)
+	if err == nil {
+		return 1 / rate, nil
+	}
+	if !errors.Is(err, ErrNoFXRate) {
+		return 0, err
+	}
+
+	if from != models.DefaultCurrency && to != models.DefaultCurrency {
+		fromRate, err := c.rate(from, models.DefaultCurrency, date)
+		if err == nil {
+			toRate, err := c.rate(models.DefaultCurrency, to, date)
+			if err == nil {
+				return fromRate * toRate, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w from %s to %s on %s", ErrNoFXRate, from, to, date.Format("2006-01-02"))
+}
+
+// ConvertFlows converts each cash flow at the rate for its own date
+func (c *FXConverter) ConvertFlows(flows []finance.CashFlow, from, to string) ([]finance.CashFlow, error) {
+	converted := make([]finance.CashFlow, len(flows))
+	for i, f := range flows {
+		rate, err := c.Rate(from, to, f.Date)
+		if err != nil {
+			return nil, err
+		}
+		converted[i] = finance.CashFlow{Date: f.Date, Amount: f.Amount * rate}
+	}
+	return converted, nil
+}
+
+// ConvertInvested converts what an investment cost into another currency at
+// the rates of the dates it was paid: each transaction's date for a holding
+// with a ledger, which is then replayed, or the purchase date without one
+func (c *FXConverter) ConvertInvested(inv models.Investment, ledger []models.InvestmentTransaction, to string) (models.Money, error) {
+	if len(ledger) == 0 {
+		date := inv.PurchaseDate
+		if date.IsZero() {
+			date = time.Now()
+		}
+		return c.Convert(inv.Invested, inv.Currency, to, date)
+	}
+
+	converted := make([]models.InvestmentTransaction, len(ledger))
+	for i, t := range ledger {
+		amount, err := c.Convert(t.Amount, inv.Currency, to, t.Date)
+		if err != nil {
+			return 0, err
+		}
+		t.Amount = amount
+		converted[i] = t
+	}
+	var replay models.Investment
+	replay.ApplyLedger(converted)
+	return replay.Invested, nil
+}
+
+// fxPair names a currency pair as "FROM/TO"
+func fxPair(from, to string) string {
+	return models.NormalizeCurrency(from) + "/" + models.NormalizeCurrency(to)
+}
+
+// pairRate looks up a direct rate for the currency pair
+func (c *FXConverter) pairRate(from, to string, date time.Time) (float64, error) {
+	key := from + "/" + to
+	rates, ok := c.rates[key]
+	if !ok {
+		if err := c.db.Where("from_currency = ? AND to_currency = ?", from, to).Order("date ASC").Find(&rates).Error; err != nil {
+			return 0, err
+		}
+		c.rates[key] = rates
+	}
+
+	idx := sort.Search(len(rates), func(i int) bool {
+		return rates[i].Date.After(date)
+	})
+	if idx == 0 || rates[idx-1].Rate <= 0 {
+		return 0, ErrNoFXRate
+	}
+	return rates[idx-1].Rate, nil
+}
+
+// ImportFXRatesCSV loads "date,from,to,rate" rows (a header row is optional),
+// overwriting rates already stored for the same pair and day. It returns the
+// number of rates loaded.
+func ImportFXRatesCSV(db *gorm.DB, r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	var rates []models.FXRate
+	for i, record := range records {
+		if len(record) < 4 {
+			return 0, fmt.Errorf("line %d: expected date,from,to,rate", i+1)
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff")))
+		if err != nil {
+			if i == 0 {
+				continue // header row
+			}
+			return 0, fmt.Errorf("line %d: invalid date %q", i+1, record[0])
+		}
+		from, to := models.NormalizeCurrency(record[1]), models.NormalizeCurrency(record[2])
+		if !models.ValidCurrency(from) || !models.ValidCurrency(to) || from == to {
+			return 0, fmt.Errorf("line %d: invalid currency pair %s/%s", i+1, record[1], record[2])
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+		if err != nil || rate <= 0 {
+			return 0, fmt.Errorf("line %d: invalid rate %q", i+1, record[3])
+		}
+		rates = append(rates, models.FXRate{FromCurrency: from, ToCurrency: to, Date: date, Rate: rate})
+	}
+	if len(rates) == 0 {
+		return 0, fmt.Errorf("no rates found")
+	}
+
+	err = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "from_currency"}, {Name: "to_currency"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "rate"}),
+	}).CreateInBatches(rates, 500).Error
+	if err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+//
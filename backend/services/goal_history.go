@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/notify"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// RecordGoalProgress saves the history events for a change of a goal from
+// previous (nil for a new goal) and passes milestones and status changes on to
+// the registered notifiers, once the surrounding transaction (if any, see
+// transactionWithGoalEvents) has committed. Each milestone is recorded once
+// per goal.
+func RecordGoalProgress(db *gorm.DB, previous *models.Goal, goal models.Goal) error {
+	goal.CalculateInflation()
+	if previous != nil {
+		before := *previous
+		before.CalculateInflation()
+		previous = &before
+	}
+
+	events := goal.ProgressEvents(previous)
+	if len(events) == 0 {
+		return nil
+	}
+
+	var reached []int
+	if err := db.Model(&models.GoalProgressEvent{}).Where("goal_id = ? AND type = ?", goal.ID, models.GoalEventMilestone).
+		Pluck("milestone", &reached).Error; err != nil {
+		return err
+	}
+	seen := make(map[int]bool)
+	for _, milestone := range reached {
+		seen[milestone] = true
+	}
+
+	for _, event := range events {
+		if event.Type == models.GoalEventMilestone && seen[event.Milestone] {
+			continue
+		}
+		if err := db.Create(&event).Error; err != nil {
+			return err
+		}
+		if event.Type != models.GoalEventProgress {
+			queueGoalEvent(db, goal, event)
+		}
+	}
+	return nil
+}
+
+// goalEventQueueKey is the context key of the goal events held back until a
+// transaction commits
+type goalEventQueueKey struct{}
+
+// goalEventQueue holds the goal events raised inside a transaction
+type goalEventQueue struct {
+	mu     sync.Mutex
+	goals  []models.Goal
+	events []models.GoalProgressEvent
+}
+
+// transactionWithGoalEvents runs fn in a database transaction, sending the goal
+// events recorded inside it only once it commits. Events of a transaction that
+// rolls back are dropped. Nested calls leave sending to the outermost one.
+func transactionWithGoalEvents(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := ctx.Value(goalEventQueueKey{}).(*goalEventQueue); ok {
+		return db.Transaction(fn)
+	}
+
+	queue := &goalEventQueue{}
+	if err := db.WithContext(context.WithValue(ctx, goalEventQueueKey{}, queue)).Transaction(fn); err != nil {
+		return err
+	}
+	for i, event := range queue.events {
+		go notify.GoalEvent(queue.goals[i], event)
+	}
+	return nil
+}
+
+// queueGoalEvent holds an event back until the transaction db belongs to
+// commits, or sends it straight away outside of one
+func queueGoalEvent(db *gorm.DB, goal models.Goal, event models.GoalProgressEvent) {
+	if ctx := db.Statement.Context; ctx != nil {
+		if queue, ok := ctx.Value(goalEventQueueKey{}).(*goalEventQueue); ok {
+			queue.mu.Lock()
+			queue.goals = append(queue.goals, goal)
+			queue.events = append(queue.events, event)
+			queue.mu.Unlock()
+			return
+		}
+	}
+	go notify.GoalEvent(goal, event)
+}
+
+//
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"investment-tracker-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// spendingMonths is how many complete months average spending is taken over
+const spendingMonths = 12
+
+// ErrNoSpending is returned when a template needs the user's spending and none is recorded
+var ErrNoSpending = errors.New("No expenses or monthly expenses recorded to size the goal from")
+
+// AverageMonthlySpending returns the user's average spending per month, in
+// currency, over the last 12 complete months (or since the first expense in
+// them). Users with no expenses in that time fall back to their monthly
+// expenses figure.
+func AverageMonthlySpending(db *gorm.DB, userID uint, currency string, now time.Time) (models.Money, error) {
+	from, to := spendingWindow(now)
+
+	var expenses []models.Expense
+	if err := db.Where("user_id = ? AND date >= ? AND date < ?", userID, from, to).Order("date ASC").Find(&expenses).Error; err != nil {
+		return 0, err
+	}
+
+	fx := NewFXConverter(db)
+	if len(expenses) == 0 {
+		var user models.User
+		if err := db.Select("id", "monthly_expenses", "base_currency").First(&user, userID).Error; err != nil {
+			return 0, err
+		}
+		if user.MonthlyExpenses <= 0 {
+			return 0, ErrNoSpending
+		}
+		return fx.Convert(user.MonthlyExpenses, user.BaseCurrency, currency, now)
+	}
+
+	var total models.Money
+	for _, expense := range expenses {
+		amount, err := fx.Convert(expense.Amount, expense.Currency, currency, expense.Date)
+		if err != nil {
+			return 0, err
+		}
+		total += amount
+	}
+
+	first := expenses[0].Date
+	months := (to.Year()-first.Year())*12 + int(to.Month()-first.Month())
+	return total.Mul(1 / float64(months)), nil
+}
+
+// InSpendingWindow reports whether an expense dated date counts towards the
+// average monthly spending as of now
+func InSpendingWindow(date, now time.Time) bool {
+	from, to := spendingWindow(now)
+	return !date.Before(from) && date.Before(to)
+}
+
+// spendingWindow returns the start of the complete months average spending is
+// taken over and the start of the current month
+func spendingWindow(now time.Time) (time.Time, time.Time) {
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return to.AddDate(0, -spendingMonths, 0), to
+}
+
+// ApplyGoalTemplate sizes a templated goal's target, reading the user's
+// average spending for templates that need it
+func ApplyGoalTemplate(db *gorm.DB, goal *models.Goal, now time.Time) error {
+	if goal.Template == "" {
+		return nil
+	}
+
+	var spending models.Money
+	if goal.UsesSpending() {
+		var err error
+		if spending, err = AverageMonthlySpending(db, goal.UserID, goal.Currency, now); err != nil {
+			return err
+		}
+	}
+	goal.ApplyTemplate(spending, now)
+	return nil
+}
+
+// RefreshTemplateGoals resizes a user's goals whose template depends on their
+// spending. Goals are left as they are when no spending is recorded.
+func RefreshTemplateGoals(db *gorm.DB, userID uint, now time.Time) error {
+	var goals []models.Goal
+	if err := db.Where("user_id = ? AND template IN ?", userID,
+		[]string{models.GoalTemplateEmergencyFund, models.GoalTemplateRetirement}).Find(&goals).Error; err != nil {
+		return err
+	}
+
+	for i := range goals {
+		goal := &goals[i]
+		previous := *goal
+		err := ApplyGoalTemplate(db, goal, now)
+		if errors.Is(err, ErrNoSpending) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		goal.UpdateStatus()
+
+		if err := db.Model(goal).Updates(map[string]interface{}{
+			"target_amount":   goal.TargetAmount,
+			"deadline":        goal.Deadline,
+			"template_inputs": goal.TemplateInputs,
+			"status":          goal.Status,
+		}).Error; err != nil {
+			return err
+		}
+		if err := RecordGoalProgress(db, &previous, *goal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//
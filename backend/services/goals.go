@@ -0,0 +1,243 @@
+package services
+
+import (
+	"errors"
+	"investment-tracker-backend/models"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RefreshGoalCurrentAmount recalculates and updates a goal's current_amount from its share of
+// each allocated investment plus its manual contributions less withdrawals, converted to the
+// goal's currency at today's rates. Amounts without an exchange rate are left out and the
+// missing pairs logged, so that one missing rate does not hold up the refresh. The goal's
+// status follows its progress, and the change is recorded in its history.
+func RefreshGoalCurrentAmount(db *gorm.DB, goalID uint) error {
+	var goal models.Goal
+	if err := db.First(&goal, goalID).Error; err != nil {
+		return err
+	}
+	previous := goal
+
+	// Find all investments allocated to this goal
+	var allocations []models.InvestmentGoalAllocation
+	if err := db.Where("goal_id = ?", goalID).Find(&allocations).Error; err != nil {
+		return err
+	}
+	var investments []models.Investment
+	if len(allocations) > 0 {
+		ids := make([]uint, len(allocations))
+		for i, a := range allocations {
+			ids[i] = a.InvestmentID
+		}
+		if err := db.Where("id IN ?", ids).Find(&investments).Error; err != nil {
+			return err
+		}
+	}
+
+	// Convert each investment's value, then count only the goal's share
+	fx := NewFXConverter(db)
+	now := time.Now()
+	converted := investments[:0]
+	for _, inv := range investments {
+		value, err := fx.Convert(inv.CurrentValue, inv.Currency, goal.Currency, now)
+		if errors.Is(err, ErrNoFXRate) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		inv.CurrentValue = value
+		converted = append(converted, inv)
+	}
+	contributions, err := GoalContributionsTotal(db, fx, goal, now)
+	if err != nil {
+		return err
+	}
+	if missing := fx.Missing(); len(missing) > 0 {
+		log.Printf("Goal %d: left out amounts without exchange rates for %s", goal.ID, strings.Join(missing, ", "))
+	}
+	goal.CurrentAmount = goal.CalculateLinkedInvestmentsTotal(converted, allocations) + contributions
+	if goal.CurrentAmount < 0 {
+		goal.CurrentAmount = 0
+	}
+	goal.UpdateStatus()
+
+	// Update the goal's current_amount and status
+	if err := db.Model(&models.Goal{}).Where("id = ?", goalID).Updates(map[string]interface{}{
+		"current_amount": goal.CurrentAmount,
+		"status":         goal.Status,
+		"updated_at":     time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	return RecordGoalProgress(db, &previous, goal)
+}
+
+// GoalContributionsTotal returns a goal's deposits less its withdrawals made up
+// to at, in the goal's currency at the rates of that day. Contributions without
+// an exchange rate are left out; fx lists their currency pairs.
+func GoalContributionsTotal(db *gorm.DB, fx *FXConverter, goal models.Goal, at time.Time) (models.Money, error) {
+	var contributions []models.GoalContribution
+	if err := db.Where("goal_id = ? AND date <= ?", goal.ID, at).Find(&contributions).Error; err != nil {
+		return 0, err
+	}
+
+	converted := contributions[:0]
+	for _, c := range contributions {
+		amount, err := fx.Convert(c.Amount, c.Currency, goal.Currency, at)
+		if errors.Is(err, ErrNoFXRate) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		c.Amount = amount
+		converted = append(converted, c)
+	}
+	return goal.CalculateContributionsTotal(converted), nil
+}
+
+// LoadGoalAllocations fetches the goal allocations of the given investments, keyed by investment ID
+func LoadGoalAllocations(db *gorm.DB, investmentIDs []uint) (map[uint][]models.InvestmentGoalAllocation, error) {
+	byInvestment := make(map[uint][]models.InvestmentGoalAllocation)
+	if len(investmentIDs) == 0 {
+		return byInvestment, nil
+	}
+
+	var allocations []models.InvestmentGoalAllocation
+	if err := db.Where("investment_id IN ?", investmentIDs).Order("percent DESC, id ASC").Find(&allocations).Error; err != nil {
+		return nil, err
+	}
+	for _, a := range allocations {
+		byInvestment[a.InvestmentID] = append(byInvestment[a.InvestmentID], a)
+	}
+	return byInvestment, nil
+}
+
+// SaveGoalAllocations replaces an investment's goal allocations, keeps its goal_id
+// pointing at the goal with the largest share and refreshes every goal whose
+// share changed
+func SaveGoalAllocations(db *gorm.DB, inv *models.Investment, allocations []models.InvestmentGoalAllocation) error {
+	if err := models.ValidateAllocations(allocations); err != nil {
+		return err
+	}
+
+	return transactionWithGoalEvents(db, func(tx *gorm.DB) error {
+		var existing []models.InvestmentGoalAllocation
+		if err := tx.Where("investment_id = ?", inv.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+
+		goalIDs := make(map[uint]bool)
+		keep := make([]uint, 0, len(allocations))
+		for i := range allocations {
+			allocations[i].ID = 0
+			allocations[i].UserID = inv.UserID
+			allocations[i].InvestmentID = inv.ID
+			goalIDs[allocations[i].GoalID] = true
+			keep = append(keep, allocations[i].GoalID)
+		}
+		for _, a := range existing {
+			goalIDs[a.GoalID] = true
+		}
+
+		remove := tx.Where("investment_id = ?", inv.ID)
+		if len(keep) > 0 {
+			remove = remove.Where("goal_id NOT IN ?", keep)
+		}
+		if err := remove.Delete(&models.InvestmentGoalAllocation{}).Error; err != nil {
+			return err
+		}
+		if len(allocations) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "investment_id"}, {Name: "goal_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"percent", "updated_at"}),
+			}).Create(&allocations).Error; err != nil {
+				return err
+			}
+		}
+
+		inv.GoalID = models.PrimaryGoalID(allocations)
+		if err := tx.Model(inv).Update("goal_id", inv.GoalID).Error; err != nil {
+			return err
+		}
+
+		for goalID := range goalIDs {
+			if err := RefreshGoalCurrentAmount(tx, goalID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RefreshInvestmentGoals refreshes every goal the given investments are allocated to
+// and returns how many goals were refreshed
+func RefreshInvestmentGoals(db *gorm.DB, investmentIDs ...uint) (int, error) {
+	if len(investmentIDs) == 0 {
+		return 0, nil
+	}
+
+	var goalIDs []uint
+	if err := db.Model(&models.InvestmentGoalAllocation{}).Where("investment_id IN ?", investmentIDs).
+		Distinct().Pluck("goal_id", &goalIDs).Error; err != nil {
+		return 0, err
+	}
+	for _, goalID := range goalIDs {
+		if err := RefreshGoalCurrentAmount(db, goalID); err != nil {
+			return 0, err
+		}
+	}
+	return len(goalIDs), nil
+}
+
+// RemoveInvestmentAllocations deletes an investment's goal allocations and
+// refreshes the goals it counted towards
+func RemoveInvestmentAllocations(db *gorm.DB, investmentID uint) error {
+	var goalIDs []uint
+	if err := db.Model(&models.InvestmentGoalAllocation{}).Where("investment_id = ?", investmentID).
+		Pluck("goal_id", &goalIDs).Error; err != nil {
+		return err
+	}
+	if err := db.Where("investment_id = ?", investmentID).Delete(&models.InvestmentGoalAllocation{}).Error; err != nil {
+		return err
+	}
+	for _, goalID := range goalIDs {
+		if err := RefreshGoalCurrentAmount(db, goalID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveGoalAllocations deletes every allocation to a goal and moves the goal_id
+// of the affected investments to their next largest allocation
+func RemoveGoalAllocations(db *gorm.DB, goalID uint) error {
+	var investmentIDs []uint
+	if err := db.Model(&models.InvestmentGoalAllocation{}).Where("goal_id = ?", goalID).
+		Pluck("investment_id", &investmentIDs).Error; err != nil {
+		return err
+	}
+	if err := db.Where("goal_id = ?", goalID).Delete(&models.InvestmentGoalAllocation{}).Error; err != nil {
+		return err
+	}
+
+	byInvestment, err := LoadGoalAllocations(db, investmentIDs)
+	if err != nil {
+		return err
+	}
+	for _, investmentID := range investmentIDs {
+		primary := models.PrimaryGoalID(byInvestment[investmentID])
+		if err := db.Model(&models.Investment{}).Where("id = ?", investmentID).Update("goal_id", primary).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//
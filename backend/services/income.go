@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"investment-tracker-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoadInvestmentIncome recalculates an investment's income and total returns
+// from its income events and ledger dividends. The caller saves the investment.
+func LoadInvestmentIncome(db *gorm.DB, inv *models.Investment) error {
+	var events []models.IncomeEvent
+	if err := db.Where("investment_id = ?", inv.ID).Find(&events).Error; err != nil {
+		return err
+	}
+
+	var dividends []models.InvestmentTransaction
+	if err := db.Where("investment_id = ? AND type = ?", inv.ID, models.TransactionDividend).Find(&dividends).Error; err != nil {
+		return err
+	}
+
+	inv.ApplyIncome(events, dividends)
+	return nil
+}
+
+// PassiveIncome totals a user's income events and ledger dividends received
+// between from and to, each converted to currency at the rate for the day it
+// was received. Payouts without an exchange rate are left out; fx lists their
+// currency pairs.
+func PassiveIncome(db *gorm.DB, fx *FXConverter, userID uint, from, to time.Time, currency string) (models.Money, error) {
+	type payout struct {
+		Amount   models.Money
+		Date     time.Time
+		Currency string
+	}
+
+	var events, dividends []payout
+	if err := db.Model(&models.IncomeEvent{}).
+		Joins("JOIN investments ON investments.id = income_events.investment_id").
+		Where("income_events.user_id = ? AND income_events.date BETWEEN ? AND ?", userID, from, to).
+		Select("income_events.amount, income_events.date, investments.currency").Scan(&events).Error; err != nil {
+		return 0, err
+	}
+	if err := db.Model(&models.InvestmentTransaction{}).
+		Joins("JOIN investments ON investments.id = investment_transactions.investment_id").
+		Where("investment_transactions.user_id = ? AND investment_transactions.type = ? AND investment_transactions.date BETWEEN ? AND ?", userID, models.TransactionDividend, from, to).
+		Select("investment_transactions.amount, investment_transactions.date, investments.currency").Scan(&dividends).Error; err != nil {
+		return 0, err
+	}
+
+	var total models.Money
+	for _, p := range append(events, dividends...) {
+		amount, err := fx.Convert(p.Amount, p.Currency, currency, p.Date)
+		if errors.Is(err, ErrNoFXRate) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		total += amount
+	}
+	return total, nil
+}
+
+//
@@ -0,0 +1,487 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"investment-tracker-backend/models"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ImportMapping names the CSV column holding each investment field. Empty
+// entries fall back to the field's own name (e.g. "current_value").
+type ImportMapping struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Symbol       string `json:"symbol"`
+	Units        string `json:"units"`
+	Invested     string `json:"invested"`
+	CurrentValue string `json:"current_value"`
+	PurchaseDate string `json:"purchase_date"`
+}
+
+// ImportRow is one parsed CSV row with its validation result
+type ImportRow struct {
+	Row          int          `json:"row"` // 1-based line number in the file
+	Name         string       `json:"name"`
+	Type         string       `json:"type"`
+	Symbol       string       `json:"symbol,omitempty"`
+	Units        float64      `json:"units"`
+	Invested     models.Money `json:"invested"`
+	CurrentValue models.Money `json:"current_value"`
+	PurchaseDate time.Time    `json:"purchase_date"`
+	Action       string       `json:"action,omitempty"`     // create or merge
+	MergeWith    *uint        `json:"merge_with,omitempty"` // Existing investment the row merges into
+	Errors       []string     `json:"errors,omitempty"`
+}
+
+// ImportPreview summarizes what an import would do
+type ImportPreview struct {
+	Rows      []ImportRow `json:"rows"`
+	Valid     int         `json:"valid"`
+	Invalid   int         `json:"invalid"`
+	Creates   int         `json:"creates"`
+	Merges    int         `json:"merges"`
+	Committed bool        `json:"committed"`
+}
+
+// importDateLayouts are the purchase date formats accepted when none is given
+var importDateLayouts = []string{"2006-01-02", "02-01-2006", "02/01/2006", "02-Jan-2006", "2 Jan 2006", time.RFC3339}
+
+// ParseInvestmentCSV reads investment rows from a CSV with a header row,
+// using mapping to find the columns. Row-level problems are reported on
+// each row rather than failing the whole file.
+func ParseInvestmentCSV(r io.Reader, mapping ImportMapping, dateLayout string) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("CSV file is empty or unreadable")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	column := func(mapped, field string) (int, bool) {
+		if mapped == "" {
+			mapped = field
+		}
+		idx, ok := columns[strings.ToLower(strings.TrimSpace(mapped))]
+		return idx, ok
+	}
+
+	required := map[string]string{"name": mapping.Name, "type": mapping.Type, "invested": mapping.Invested}
+	for field, mapped := range required {
+		if _, ok := column(mapped, field); !ok {
+			if mapped == "" {
+				mapped = field
+			}
+			return nil, fmt.Errorf("column %q for %s not found in CSV header", mapped, field)
+		}
+	}
+
+	layouts := importDateLayouts
+	if dateLayout != "" {
+		layouts = []string{dateLayout}
+	}
+
+	var rows []ImportRow
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		value := func(mapped, field string) string {
+			if idx, ok := column(mapped, field); ok && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+
+		row := ImportRow{
+			Row:    line,
+			Name:   value(mapping.Name, "name"),
+			Type:   value(mapping.Type, "type"),
+			Symbol: value(mapping.Symbol, "symbol"),
+		}
+		if row.Name == "" && row.Type == "" && value(mapping.Invested, "invested") == "" {
+			continue // blank line
+		}
+
+		if row.Name == "" {
+			row.Errors = append(row.Errors, "Investment name is required")
+		}
+		if row.Type == "" {
+			row.Errors = append(row.Errors, "Investment type is required")
+		}
+
+		var parseErr error
+		if row.Invested, parseErr = parseImportMoney(value(mapping.Invested, "invested")); parseErr != nil || row.Invested <= 0 {
+			row.Errors = append(row.Errors, "Invested amount must be a number greater than 0")
+		}
+		if row.Units, parseErr = parseImportAmount(value(mapping.Units, "units")); parseErr != nil || row.Units < 0 {
+			row.Errors = append(row.Errors, "Units must be a non-negative number")
+		}
+		if row.CurrentValue, parseErr = parseImportMoney(value(mapping.CurrentValue, "current_value")); parseErr != nil || row.CurrentValue < 0 {
+			row.Errors = append(row.Errors, "Current value must be a non-negative number")
+		}
+		if row.CurrentValue == 0 {
+			row.CurrentValue = row.Invested
+		}
+
+		if raw := value(mapping.PurchaseDate, "purchase_date"); raw != "" {
+			row.PurchaseDate = parseImportDate(raw, layouts)
+			if row.PurchaseDate.IsZero() {
+				row.Errors = append(row.Errors, fmt.Sprintf("Invalid purchase date %q", raw))
+			}
+		} else {
+			row.PurchaseDate = time.Now()
+		}
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("CSV file has no investment rows")
+	}
+	return rows, nil
+}
+
+// PlanInvestmentImport marks each valid row as a new holding or a merge into
+// an existing one (or an earlier row) with the same symbol, or name and type.
+// Rows that cannot be reconciled with an existing holding's ledger are
+// rejected.
+func PlanInvestmentImport(db *gorm.DB, userID uint, rows []ImportRow) (ImportPreview, error) {
+	preview := ImportPreview{Rows: rows}
+
+	var existing []models.Investment
+	if err := db.Where("user_id = ?", userID).Find(&existing).Error; err != nil {
+		return preview, err
+	}
+	known := make(map[string]uint)
+	byID := make(map[uint]models.Investment)
+	for _, inv := range existing {
+		for _, key := range holdingKeys(inv.Name, inv.Type, inv.Symbol) {
+			known[key] = inv.ID
+		}
+		byID[inv.ID] = inv
+	}
+
+	var transactions []models.InvestmentTransaction
+	if err := db.Where("user_id = ?", userID).Find(&transactions).Error; err != nil {
+		return preview, err
+	}
+	ledgers := make(map[uint][]models.InvestmentTransaction)
+	for _, t := range transactions {
+		ledgers[t.InvestmentID] = append(ledgers[t.InvestmentID], t)
+	}
+
+	now := time.Now()
+	replaced := make(map[uint]bool)
+
+	seen := make(map[string]bool)
+	for i := range preview.Rows {
+		row := &preview.Rows[i]
+		if len(row.Errors) > 0 {
+			preview.Invalid++
+			continue
+		}
+		preview.Valid++
+
+		row.Action = "create"
+		keys := holdingKeys(row.Name, row.Type, row.Symbol)
+		for _, key := range keys {
+			if id, ok := known[key]; ok {
+				row.Action = "merge"
+				row.MergeWith = &id
+				break
+			}
+			if seen[key] {
+				row.Action = "merge"
+				break
+			}
+		}
+		if row.MergeWith != nil && !replaced[*row.MergeWith] {
+			replaced[*row.MergeWith] = true
+			if ledger := ledgers[*row.MergeWith]; len(ledger) > 0 {
+				if _, err := reconcileStatement(byID[*row.MergeWith], ledger, *row, now); err != nil {
+					row.Errors = append(row.Errors, err.Error())
+					row.Action, row.MergeWith = "", nil
+					preview.Valid--
+					preview.Invalid++
+					continue
+				}
+			}
+		}
+		if row.Action == "merge" {
+			preview.Merges++
+		} else {
+			preview.Creates++
+		}
+		for _, key := range keys {
+			seen[key] = true
+		}
+	}
+	return preview, nil
+}
+
+// CommitInvestmentImport applies a planned import in a single database
+// transaction. Every row must be valid.
+func CommitInvestmentImport(db *gorm.DB, userID uint, preview *ImportPreview) error {
+	if preview.Invalid > 0 {
+		return errors.New("import has invalid rows; fix them before committing")
+	}
+
+	// Statement figures are reconciled with ledgers as of the import
+	importedAt := time.Now()
+	var merged []uint
+	err := transactionWithGoalEvents(db, func(tx *gorm.DB) error {
+		// Holdings created earlier in this import, for rows that repeat, and
+		// existing holdings already replaced by a row of this import
+		created := make(map[string]*models.Investment)
+		replaced := make(map[uint]bool)
+
+		for _, row := range preview.Rows {
+			keys := holdingKeys(row.Name, row.Type, row.Symbol)
+
+			var earlier *models.Investment
+			for _, key := range keys {
+				if created[key] != nil {
+					earlier = created[key]
+					break
+				}
+			}
+
+			var inv models.Investment
+			switch {
+			case row.MergeWith != nil:
+				if err := tx.Where("id = ? AND user_id = ?", *row.MergeWith, userID).First(&inv).Error; err != nil {
+					return fmt.Errorf("row %d: investment to merge into not found", row.Row)
+				}
+			case earlier != nil:
+				inv = *earlier
+			default:
+				inv = models.Investment{
+					UserID:       userID,
+					Name:         row.Name,
+					Type:         row.Type,
+					Symbol:       row.Symbol,
+					Invested:     row.Invested,
+					CurrentValue: row.CurrentValue,
+					Units:        row.Units,
+					PurchaseDate: row.PurchaseDate,
+				}
+				inv.CalculateReturns()
+				if err := UpdateInvestmentStatus(tx, &inv); err != nil {
+					return err
+				}
+				if err := tx.Create(&inv).Error; err != nil {
+					return fmt.Errorf("row %d: %v", row.Row, err)
+				}
+				for _, key := range keys {
+					created[key] = &inv
+				}
+				continue
+			}
+
+			// A statement is a snapshot, so its first row for an existing holding
+			// replaces the holding's figures and further rows add lots to it
+			replace := row.MergeWith != nil && !replaced[inv.ID]
+			if err := mergeImportRow(tx, &inv, row, replace, importedAt); err != nil {
+				return fmt.Errorf("row %d: %v", row.Row, err)
+			}
+			if row.MergeWith != nil {
+				replaced[inv.ID] = true
+			}
+			if earlier != nil {
+				*earlier = inv
+			}
+			merged = append(merged, inv.ID)
+		}
+
+		_, err := RefreshInvestmentGoals(tx, merged...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	preview.Committed = true
+	return nil
+}
+
+// mergeImportRow applies a row to an existing investment. With replace the
+// row's figures become the holding's; otherwise the row's lot is added to it.
+// Holdings with a transaction ledger get a transaction instead of edited
+// totals (see reconcileStatement) and take the row's value.
+func mergeImportRow(tx *gorm.DB, inv *models.Investment, row ImportRow, replace bool, importedAt time.Time) error {
+	var transactions []models.InvestmentTransaction
+	if err := tx.Where("investment_id = ?", inv.ID).Find(&transactions).Error; err != nil {
+		return err
+	}
+
+	if len(transactions) > 0 {
+		value := inv.CurrentValue + row.CurrentValue
+		adjustment := &models.InvestmentTransaction{
+			Type:   models.TransactionBuy,
+			Units:  row.Units,
+			Amount: row.Invested,
+			Date:   row.PurchaseDate,
+		}
+		if row.Units > 0 {
+			adjustment.Price = row.Invested.Float64() / row.Units
+		}
+		if replace {
+			value = row.CurrentValue
+			var err error
+			if adjustment, err = reconcileStatement(*inv, transactions, row, importedAt); err != nil {
+				return err
+			}
+		}
+
+		if adjustment != nil {
+			adjustment.UserID = inv.UserID
+			adjustment.InvestmentID = inv.ID
+			adjustment.Notes = "Imported from CSV"
+			if err := tx.Create(adjustment).Error; err != nil {
+				return err
+			}
+			transactions = append(transactions, *adjustment)
+		}
+		inv.ApplyLedger(transactions)
+		inv.CurrentValue = value
+		inv.CalculateReturns()
+	} else {
+		if replace {
+			inv.Invested, inv.CurrentValue, inv.Units = 0, 0, 0
+		}
+		inv.Invested += row.Invested
+		inv.CurrentValue += row.CurrentValue
+		inv.Units += row.Units
+		inv.CalculateReturns()
+	}
+	if row.PurchaseDate.Before(inv.PurchaseDate) {
+		inv.PurchaseDate = row.PurchaseDate
+	}
+	if inv.Symbol == "" {
+		inv.Symbol = row.Symbol
+	}
+	if err := UpdateInvestmentStatus(tx, inv); err != nil {
+		return err
+	}
+
+	return tx.Save(inv).Error
+}
+
+// reconcileStatement returns the transaction, dated at the statement date,
+// that brings a holding's ledger to a statement row's units and invested
+// amount, or nil when they already match. More units are bought for the
+// extra cost, fewer are sold at the statement's price, and a higher cost for
+// the same units is a Buy of no units. Rows without units, or with less
+// invested for as many units, cannot be expressed as a transaction and are
+// rejected.
+func reconcileStatement(inv models.Investment, ledger []models.InvestmentTransaction, row ImportRow, date time.Time) (*models.InvestmentTransaction, error) {
+	held := models.UnitsHeld(ledger, date)
+	if row.Units == 0 && held > 1e-9 {
+		return nil, fmt.Errorf("Units are required to reconcile with the %g units in the transaction ledger", held)
+	}
+
+	units := 0.0
+	if row.Units > 0 {
+		units = row.Units - held
+	}
+	amount := row.Invested - inv.Invested
+
+	switch {
+	case units < -1e-9:
+		// Fewer units than the ledger holds on the date: sell the difference at
+		// the statement's price
+		sold := -units
+		proceeds := row.CurrentValue.Mul(sold / row.Units)
+		return &models.InvestmentTransaction{
+			Type:   models.TransactionSell,
+			Units:  sold,
+			Price:  proceeds.Float64() / sold,
+			Amount: proceeds,
+			Date:   date,
+		}, nil
+	case amount < 0:
+		return nil, fmt.Errorf("Invested amount is %s less than the transaction ledger for as many or more units; record a transaction to correct it", -amount)
+	case units > 1e-9:
+		return &models.InvestmentTransaction{
+			Type:   models.TransactionBuy,
+			Units:  units,
+			Price:  amount.Float64() / units,
+			Amount: amount,
+			Date:   date,
+		}, nil
+	case amount > 0:
+		return &models.InvestmentTransaction{Type: models.TransactionBuy, Amount: amount, Date: date}, nil
+	}
+	return nil, nil
+}
+
+// holdingKeys identifies duplicate holdings: by symbol when there is one,
+// then by name and type
+func holdingKeys(name, investmentType, symbol string) []string {
+	investmentType = strings.ToLower(strings.TrimSpace(investmentType))
+	keys := []string{investmentType + "|name|" + strings.ToLower(strings.Join(strings.Fields(name), " "))}
+	if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+		keys = append([]string{investmentType + "|symbol|" + symbol}, keys...)
+	}
+	return keys
+}
+
+// parseImportAmount parses a number, ignoring currency symbols and thousands separators
+func parseImportAmount(raw string) (float64, error) {
+	cleaned := cleanImportNumber(raw)
+	if cleaned == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(cleaned, 64)
+}
+
+// parseImportMoney parses an amount exactly, ignoring currency symbols and thousands separators
+func parseImportMoney(raw string) (models.Money, error) {
+	cleaned := cleanImportNumber(raw)
+	if cleaned == "" {
+		return 0, nil
+	}
+	return models.ParseMoney(cleaned)
+}
+
+// cleanImportNumber strips everything but digits, the decimal point and the sign
+func cleanImportNumber(raw string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return -1
+	}, raw)
+}
+
+// parseImportDate tries each layout in turn, returning the zero time if none match
+func parseImportDate(raw string, layouts []string) time.Time {
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, raw); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
+
+//
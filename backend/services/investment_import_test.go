@@ -0,0 +1,95 @@
+package services
+
+import (
+	"investment-tracker-backend/models"
+	"testing"
+	"time"
+)
+
+func TestReconcileStatement(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2024, m, d, 0, 0, 0, 0, time.UTC) }
+	ledger := []models.InvestmentTransaction{
+		{ID: 1, Type: models.TransactionBuy, Units: 10, Price: 100, Amount: models.NewMoney(1000), Date: day(1, 1)},
+		{ID: 2, Type: models.TransactionBuy, Units: 10, Price: 120, Amount: models.NewMoney(1200), Date: day(3, 1)},
+	}
+	var inv models.Investment
+	inv.ApplyLedger(ledger)
+	statementDate := day(6, 30)
+
+	tests := []struct {
+		name       string
+		row        ImportRow
+		wantType   string // empty for no transaction
+		wantUnits  float64
+		wantAmount models.Money
+		wantErr    bool
+	}{
+		{
+			name: "statement matches the ledger",
+			row:  ImportRow{Units: 20, Invested: models.NewMoney(2200), CurrentValue: models.NewMoney(2600)},
+		},
+		{
+			name:       "more units are bought",
+			row:        ImportRow{Units: 25, Invested: models.NewMoney(2850), CurrentValue: models.NewMoney(3250)},
+			wantType:   models.TransactionBuy,
+			wantUnits:  5,
+			wantAmount: models.NewMoney(650),
+		},
+		{
+			name:       "fewer units are sold at the statement's price",
+			row:        ImportRow{Units: 15, Invested: models.NewMoney(1650), CurrentValue: models.NewMoney(1950)},
+			wantType:   models.TransactionSell,
+			wantUnits:  5,
+			wantAmount: models.NewMoney(650),
+		},
+		{
+			name:       "a higher cost for the same units",
+			row:        ImportRow{Units: 20, Invested: models.NewMoney(2300), CurrentValue: models.NewMoney(2600)},
+			wantType:   models.TransactionBuy,
+			wantAmount: models.NewMoney(100),
+		},
+		{
+			name:    "a lower cost for the same units",
+			row:     ImportRow{Units: 20, Invested: models.NewMoney(2000), CurrentValue: models.NewMoney(2600)},
+			wantErr: true,
+		},
+		{
+			name:    "no units on the statement",
+			row:     ImportRow{Invested: models.NewMoney(2200), CurrentValue: models.NewMoney(2600)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adjustment, err := reconcileStatement(inv, ledger, tt.row, statementDate)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("reconcileStatement() = %+v, want an error", adjustment)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("reconcileStatement() error = %v", err)
+			}
+			if tt.wantType == "" {
+				if adjustment != nil {
+					t.Errorf("reconcileStatement() = %+v, want no transaction", adjustment)
+				}
+				return
+			}
+			if adjustment == nil {
+				t.Fatal("reconcileStatement() = nil, want a transaction")
+			}
+			if adjustment.Type != tt.wantType || adjustment.Units != tt.wantUnits || adjustment.Amount != tt.wantAmount {
+				t.Errorf("reconcileStatement() = %s %v units for %s, want %s %v units for %s",
+					adjustment.Type, adjustment.Units, adjustment.Amount, tt.wantType, tt.wantUnits, tt.wantAmount)
+			}
+			if !adjustment.Date.Equal(statementDate) {
+				t.Errorf("Date = %s, want the statement date %s", adjustment.Date, statementDate)
+			}
+		})
+	}
+}
+
+//
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"errors"
+	"investment-tracker-backend/models"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Strategies for splitting a month's savings across goals
+const (
+	PlanStrategyPriority     = "priority"     // Fill goals in order of priority, then deadline
+	PlanStrategyProportional = "proportional" // Split in proportion to what each goal still needs
+	PlanStrategyDeadline     = "deadline"     // Split in proportion to what each goal still needs per month left
+)
+
+// PlanStrategies lists the supported savings plan strategies
+var PlanStrategies = []string{PlanStrategyPriority, PlanStrategyProportional, PlanStrategyDeadline}
+
+// undatedGoalMonths is the horizon a goal without a deadline is weighted with by the deadline strategy
+const undatedGoalMonths = 10 * 12
+
+// ErrNoSavings is returned when the budget has no savings to plan with
+var ErrNoSavings = errors.New("The budget has no savings to allocate")
+
+// PlannedGoal is one goal's part of a savings plan. Amounts are in the
+// budget's currency except GoalAmount, which is in the goal's.
+type PlannedGoal struct {
+	GoalID     uint          `json:"goal_id"`
+	Name       string        `json:"name"`
+	Priority   string        `json:"priority"`
+	Deadline   *time.Time    `json:"deadline,omitempty"`
+	Gap        models.Money  `json:"gap"`                                     // Left to reach the inflated target
+	Required   *models.Money `json:"required_monthly_contribution,omitempty"` // Needed each month to meet the deadline
+	Amount     models.Money  `json:"amount"`
+	GoalAmount models.Money  `json:"goal_amount"`
+	Currency   string        `json:"currency"` // The goal's currency
+
+	months int // Months left until the deadline, 0 without one
+}
+
+// SavingsPlan splits a month's budget savings across the user's open goals
+type SavingsPlan struct {
+	Month       string        `json:"month"`
+	BudgetID    uint          `json:"budget_id"`
+	Strategy    string        `json:"strategy"`
+	Currency    string        `json:"currency"` // The budget's currency
+	Savings     models.Money  `json:"savings"`
+	Allocated   models.Money  `json:"allocated"`
+	Unallocated models.Money  `json:"unallocated"` // Left over once every goal's gap is filled
+	Goals       []PlannedGoal `json:"goals"`
+	Committed   bool          `json:"committed"`
+
+	MissingFXRates []string `json:"missing_fx_rates,omitempty"` // Currency pairs of goals left out for lack of a rate
+}
+
+// ValidPlanStrategy reports whether strategy is a supported savings plan strategy
+func ValidPlanStrategy(strategy string) bool {
+	for _, s := range PlanStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanSavings splits the budget's savings across the user's goals that are not
+// yet completed. No goal is given more than it still needs to reach its
+// inflated target; what is left over stays unallocated. Goals whose currency
+// has no exchange rate to the budget's are left out of the plan.
+func PlanSavings(db *gorm.DB, budget models.Budget, strategy string, assumptions ReturnAssumptions, now time.Time) (SavingsPlan, error) {
+	plan := SavingsPlan{
+		Month:    budget.Month,
+		BudgetID: budget.ID,
+		Strategy: strategy,
+		Currency: budget.Currency,
+		Savings:  budget.Savings,
+		Goals:    []PlannedGoal{},
+	}
+	if budget.Savings <= 0 {
+		return plan, ErrNoSavings
+	}
+
+	var goals []models.Goal
+	if err := db.Where("user_id = ? AND status <> ?", budget.UserID, "Completed").Order("id ASC").Find(&goals).Error; err != nil {
+		return plan, err
+	}
+	if err := ProjectGoals(db, goals, assumptions, now); err != nil {
+		return plan, err
+	}
+
+	fx := NewFXConverter(db)
+	for _, goal := range goals {
+		goal.CalculateInflation()
+		rate, err := fx.Rate(goal.Currency, budget.Currency, now)
+		if errors.Is(err, ErrNoFXRate) {
+			continue
+		}
+		if err != nil {
+			return plan, err
+		}
+		gap := (goal.InflatedTargetAmount - goal.CurrentAmount).Mul(rate)
+		if gap <= 0 {
+			continue
+		}
+
+		planned := PlannedGoal{
+			GoalID:   goal.ID,
+			Name:     goal.Name,
+			Priority: goal.Priority,
+			Gap:      gap,
+			Currency: goal.Currency,
+		}
+		if !goal.Deadline.IsZero() {
+			deadline := goal.Deadline
+			planned.Deadline = &deadline
+			planned.months = goal.MonthsRemaining(now)
+		}
+		if goal.Projection != nil && goal.Projection.RequiredMonthlyContribution != nil {
+			required := goal.Projection.RequiredMonthlyContribution.Mul(rate)
+			if required > gap {
+				required = gap
+			}
+			if required < 0 {
+				required = 0
+			}
+			planned.Required = &required
+		}
+		plan.Goals = append(plan.Goals, planned)
+	}
+
+	switch strategy {
+	case PlanStrategyProportional:
+		weights := make([]float64, len(plan.Goals))
+		for i, g := range plan.Goals {
+			weights[i] = g.Gap.Float64()
+		}
+		splitByWeight(plan.Goals, weights, budget.Savings)
+	case PlanStrategyDeadline:
+		weights := make([]float64, len(plan.Goals))
+		for i, g := range plan.Goals {
+			months := g.months
+			if g.Deadline == nil {
+				months = undatedGoalMonths
+			}
+			if months < 1 {
+				months = 1
+			}
+			weights[i] = g.Gap.Float64() / float64(months)
+		}
+		splitByWeight(plan.Goals, weights, budget.Savings)
+	default:
+		fillByPriority(plan.Goals, budget.Savings)
+	}
+
+	for i := range plan.Goals {
+		g := &plan.Goals[i]
+		amount, err := fx.Convert(g.Amount, budget.Currency, g.Currency, now)
+		if err != nil {
+			return plan, err
+		}
+		g.GoalAmount = amount
+		plan.Allocated += g.Amount
+	}
+	plan.Unallocated = plan.Savings - plan.Allocated
+	plan.MissingFXRates = fx.Missing()
+	return plan, nil
+}
+
+// fillByPriority hands savings out like a waterfall: goals in order of
+// priority, then earliest deadline, first get what they need this month to
+// meet their deadline, and whatever is left then fills their gaps in the same
+// order
+func fillByPriority(goals []PlannedGoal, savings models.Money) {
+	order := make([]int, len(goals))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ga, gb := goals[order[a]], goals[order[b]]
+		if ra, rb := models.PriorityRank(ga.Priority), models.PriorityRank(gb.Priority); ra != rb {
+			return ra < rb
+		}
+		if (ga.Deadline == nil) != (gb.Deadline == nil) {
+			return ga.Deadline != nil
+		}
+		if ga.Deadline != nil && !ga.Deadline.Equal(*gb.Deadline) {
+			return ga.Deadline.Before(*gb.Deadline)
+		}
+		return ga.GoalID < gb.GoalID
+	})
+
+	remaining := savings
+	give := func(g *PlannedGoal, want models.Money) {
+		if want > remaining {
+			want = remaining
+		}
+		if want > 0 {
+			g.Amount += want
+			remaining -= want
+		}
+	}
+
+	for _, i := range order {
+		need := goals[i].Gap
+		if goals[i].Required != nil {
+			need = *goals[i].Required
+		}
+		give(&goals[i], need)
+	}
+	for _, i := range order {
+		give(&goals[i], goals[i].Gap-goals[i].Amount)
+	}
+}
+
+// splitByWeight shares savings out in proportion to weights. A goal whose
+// share would pass its gap gets just the gap, and the rest is shared again
+// among the others.
+func splitByWeight(goals []PlannedGoal, weights []float64, savings models.Money) {
+	remaining := savings
+	var open []int
+	for i := range goals {
+		if weights[i] > 0 {
+			open = append(open, i)
+		}
+	}
+
+	for remaining > 0 && len(open) > 0 {
+		var total float64
+		for _, i := range open {
+			total += weights[i]
+		}
+
+		pool := remaining
+		next := open[:0]
+		for _, i := range open {
+			share := pool.Mul(weights[i] / total)
+			if room := goals[i].Gap - goals[i].Amount; share >= room {
+				share = room
+			} else {
+				next = append(next, i)
+			}
+			if share > remaining {
+				share = remaining
+			}
+			goals[i].Amount += share
+			remaining -= share
+		}
+
+		// Nobody was capped, so all that is left is rounding: hand it to the
+		// goals in turn
+		if len(next) == len(open) {
+			for _, i := range next {
+				share := goals[i].Gap - goals[i].Amount
+				if share > remaining {
+					share = remaining
+				}
+				goals[i].Amount += share
+				remaining -= share
+			}
+			return
+		}
+		open = next
+	}
+}
+
+// CommitSavingsPlan records the plan's amounts as the planned contributions
+// of its month, replacing any plan committed for that month before
+func CommitSavingsPlan(db *gorm.DB, userID uint, plan *SavingsPlan) ([]models.PlannedContribution, error) {
+	contributions := []models.PlannedContribution{}
+	for _, g := range plan.Goals {
+		if g.Amount <= 0 {
+			continue
+		}
+		contributions = append(contributions, models.PlannedContribution{
+			UserID:   userID,
+			Month:    plan.Month,
+			BudgetID: plan.BudgetID,
+			GoalID:   g.GoalID,
+			Strategy: plan.Strategy,
+			Amount:   g.GoalAmount,
+			Currency: g.Currency,
+		})
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND month = ?", userID, plan.Month).Delete(&models.PlannedContribution{}).Error; err != nil {
+			return err
+		}
+		if len(contributions) == 0 {
+			return nil
+		}
+		return tx.Create(&contributions).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	plan.Committed = true
+	return contributions, nil
+}
+
+//
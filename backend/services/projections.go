@@ -0,0 +1,225 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"investment-tracker-backend/models"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReturnAssumptions are the expected annual returns, in percent, goals are
+// projected with. A rate for the investment's type wins over one for its
+// asset class, which wins over the default. Volatility, also an annual
+// percentage, is set per asset class for simulations.
+type ReturnAssumptions struct {
+	ByType            map[string]float64 `json:"by_type"`
+	ByAssetClass      map[string]float64 `json:"by_asset_class"`
+	Default           float64            `json:"default"`
+	Volatility        map[string]float64 `json:"volatility"`
+	DefaultVolatility float64            `json:"default_volatility"`
+}
+
+// DefaultReturnAssumptions are long-run returns for Indian asset classes
+func DefaultReturnAssumptions() ReturnAssumptions {
+	return ReturnAssumptions{
+		ByType: map[string]float64{},
+		ByAssetClass: map[string]float64{
+			models.AssetClassEquity:     12,
+			models.AssetClassDebt:       7,
+			models.AssetClassGold:       8,
+			models.AssetClassRealEstate: 8,
+			models.AssetClassCash:       4,
+		},
+		Default: 6,
+		Volatility: map[string]float64{
+			models.AssetClassEquity:     18,
+			models.AssetClassDebt:       4,
+			models.AssetClassGold:       15,
+			models.AssetClassRealEstate: 10,
+			models.AssetClassCash:       1,
+		},
+		DefaultVolatility: 10,
+	}
+}
+
+// LoadReturnAssumptions reads expected returns from a JSON file on top of the defaults
+func LoadReturnAssumptions(path string) (ReturnAssumptions, error) {
+	assumptions := DefaultReturnAssumptions()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return assumptions, fmt.Errorf("failed to read return assumptions: %w", err)
+	}
+
+	var overrides ReturnAssumptions
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return assumptions, fmt.Errorf("invalid return assumptions: %w", err)
+	}
+	for investmentType, rate := range overrides.ByType {
+		assumptions.ByType[strings.ToLower(investmentType)] = rate
+	}
+	for class, rate := range overrides.ByAssetClass {
+		assumptions.ByAssetClass[class] = rate
+	}
+	if overrides.Default != 0 {
+		assumptions.Default = overrides.Default
+	}
+	for class, volatility := range overrides.Volatility {
+		assumptions.Volatility[class] = volatility
+	}
+	if overrides.DefaultVolatility != 0 {
+		assumptions.DefaultVolatility = overrides.DefaultVolatility
+	}
+	return assumptions, nil
+}
+
+// ExpectedReturn returns the annual return expected from an investment
+func (a ReturnAssumptions) ExpectedReturn(inv models.Investment) float64 {
+	if rate, ok := a.ByType[strings.ToLower(strings.TrimSpace(inv.Type))]; ok {
+		return rate
+	}
+	if rate, ok := a.ByAssetClass[inv.AssetClass()]; ok {
+		return rate
+	}
+	return a.Default
+}
+
+// VolatilityOf returns the annual volatility of an investment's asset class
+func (a ReturnAssumptions) VolatilityOf(inv models.Investment) float64 {
+	if volatility, ok := a.Volatility[inv.AssetClass()]; ok {
+		return volatility
+	}
+	return a.DefaultVolatility
+}
+
+// ProjectGoals fills in each goal's projection. The expected return is the
+// average of the goal's investments weighted by its share of their value, and
+// the monthly contribution is the goal's own plus its share of active SIPs,
+// all in the goal's currency. Investments without an exchange rate are left
+// out and their currency pairs listed on the projection.
+func ProjectGoals(db *gorm.DB, goals []models.Goal, assumptions ReturnAssumptions, now time.Time) error {
+	holdings, missing, err := loadGoalHoldings(db, goals, now)
+	if err != nil {
+		return err
+	}
+
+	for i := range goals {
+		goal := &goals[i]
+		monthly := goal.MonthlyContribution
+		var weighted, weights float64
+		for _, h := range holdings[goal.ID] {
+			weighted += h.Value.Float64() * assumptions.ExpectedReturn(h.Investment)
+			weights += h.Value.Float64()
+			monthly += h.Monthly
+		}
+
+		expectedReturn := assumptions.Default
+		if weights > 0 {
+			expectedReturn = weighted / weights
+		}
+		projection := goal.Project(expectedReturn, monthly, now)
+		projection.MissingFXRates = missing[goal.ID]
+		goal.Projection = &projection
+	}
+	return nil
+}
+
+// goalHolding is a goal's share of one investment, in the goal's currency
+type goalHolding struct {
+	Investment models.Investment
+	Value      models.Money // Share of the current value
+	Monthly    models.Money // Share of the active SIPs, per month
+}
+
+// loadGoalHoldings returns the holdings of each goal, keyed by goal ID, and
+// the currency pairs of the holdings left out of each goal for lack of an
+// exchange rate
+func loadGoalHoldings(db *gorm.DB, goals []models.Goal, now time.Time) (map[uint][]goalHolding, map[uint][]string, error) {
+	holdings := make(map[uint][]goalHolding)
+	missing := make(map[uint][]string)
+	if len(goals) == 0 {
+		return holdings, missing, nil
+	}
+
+	goalIDs := make([]uint, len(goals))
+	for i, goal := range goals {
+		goalIDs[i] = goal.ID
+	}
+	var allocations []models.InvestmentGoalAllocation
+	if err := db.Where("goal_id IN ?", goalIDs).Order("id ASC").Find(&allocations).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(allocations) == 0 {
+		return holdings, missing, nil
+	}
+
+	investmentIDs := make([]uint, len(allocations))
+	for i, a := range allocations {
+		investmentIDs[i] = a.InvestmentID
+	}
+	var found []models.Investment
+	if err := db.Where("id IN ?", investmentIDs).Find(&found).Error; err != nil {
+		return nil, nil, err
+	}
+	investments := make(map[uint]models.Investment)
+	for _, inv := range found {
+		investments[inv.ID] = inv
+	}
+
+	var active []models.SIP
+	if err := db.Where("investment_id IN ? AND active = ?", investmentIDs, true).Find(&active).Error; err != nil {
+		return nil, nil, err
+	}
+	sips := make(map[uint][]models.SIP)
+	for _, sip := range active {
+		if sip.EndDate == nil || !sip.EndDate.Before(now) {
+			sips[sip.InvestmentID] = append(sips[sip.InvestmentID], sip)
+		}
+	}
+
+	currencies := make(map[uint]string)
+	for _, goal := range goals {
+		currencies[goal.ID] = goal.Currency
+	}
+
+	fx := NewFXConverter(db)
+	for _, a := range allocations {
+		inv, ok := investments[a.InvestmentID]
+		if !ok {
+			continue
+		}
+
+		rate, err := fx.Rate(inv.Currency, currencies[a.GoalID], now)
+		if errors.Is(err, ErrNoFXRate) {
+			missing[a.GoalID] = appendPair(missing[a.GoalID], fxPair(inv.Currency, currencies[a.GoalID]))
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		value := a.Share(inv.CurrentValue).Mul(rate)
+		holding := goalHolding{Investment: inv, Value: value}
+		for _, sip := range sips[inv.ID] {
+			holding.Monthly += a.Share(sip.MonthlyAmount()).Mul(rate)
+		}
+		holdings[a.GoalID] = append(holdings[a.GoalID], holding)
+	}
+	return holdings, missing, nil
+}
+
+// appendPair adds a currency pair to a list unless it is already there
+func appendPair(pairs []string, pair string) []string {
+	for _, p := range pairs {
+		if p == pair {
+			return pairs
+		}
+	}
+	return append(pairs, pair)
+}
+
+//
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/pricing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RevaluationSummary reports the outcome of a revaluation run
+type RevaluationSummary struct {
+	Checked  int      `json:"checked"`
+	Revalued int      `json:"revalued"`
+	Missing  []string `json:"missing_symbols"` // Symbols the provider had no price for
+	Goals    int      `json:"goals_refreshed"`
+}
+
+// RevalueInvestments prices every investment that has a symbol and units held,
+// recalculates returns and status, and refreshes the goals they are linked to
+func RevalueInvestments(db *gorm.DB, provider pricing.PriceProvider, date time.Time) (RevaluationSummary, error) {
+	var investments []models.Investment
+	if err := db.Where("symbol <> '' AND units > 0").Find(&investments).Error; err != nil {
+		return RevaluationSummary{Missing: []string{}}, err
+	}
+	return revalueAll(db, investments, provider, date)
+}
+
+// revalueAll prices each investment, saves the ones the provider knows about
+// and refreshes every goal touched
+func revalueAll(db *gorm.DB, investments []models.Investment, provider pricing.PriceProvider, date time.Time) (RevaluationSummary, error) {
+	summary := RevaluationSummary{Missing: []string{}}
+
+	rules := NewStatusRules(db)
+	missing := make(map[string]bool)
+	var revalued []uint
+	for i := range investments {
+		inv := &investments[i]
+		summary.Checked++
+
+		price, err := provider.Price(inv.Symbol, date)
+		if errors.Is(err, pricing.ErrPriceNotFound) {
+			if !missing[inv.Symbol] {
+				missing[inv.Symbol] = true
+				summary.Missing = append(summary.Missing, inv.Symbol)
+			}
+			continue
+		}
+		if err != nil {
+			return summary, err
+		}
+
+		if err := revalueInvestment(db, rules, inv, price); err != nil {
+			return summary, err
+		}
+		summary.Revalued++
+		revalued = append(revalued, inv.ID)
+	}
+
+	goals, err := RefreshInvestmentGoals(db, revalued...)
+	if err != nil {
+		return summary, err
+	}
+	summary.Goals = goals
+	return summary, nil
+}
+
+// revalueInvestment sets the current value from a unit price and saves the
+// recalculated returns and status
+func revalueInvestment(db *gorm.DB, rules *StatusRules, inv *models.Investment, price float64) error {
+	inv.CurrentValue = models.ValueAtPrice(inv.Units, price)
+	inv.CalculateReturns()
+	if err := rules.Apply(inv); err != nil {
+		return err
+	}
+
+	return db.Model(inv).Updates(map[string]interface{}{
+		"current_value": inv.CurrentValue,
+		"returns":       inv.Returns,
+		"status":        inv.Status,
+		"peak_value":    inv.PeakValue,
+	}).Error
+}
+
+//
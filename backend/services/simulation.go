@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"investment-tracker-backend/finance"
+	"investment-tracker-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoDeadline is returned when simulating a goal that has no deadline
+var ErrNoDeadline = errors.New("Goal has no deadline to simulate towards")
+
+// SimulationBand holds percentiles of a goal's simulated value on a date
+type SimulationBand struct {
+	Date time.Time    `json:"date"`
+	P10  models.Money `json:"p10"`
+	P25  models.Money `json:"p25"`
+	P50  models.Money `json:"p50"`
+	P75  models.Money `json:"p75"`
+	P90  models.Money `json:"p90"`
+}
+
+// GoalSimulation is the outcome of a Monte Carlo simulation of a goal
+type GoalSimulation struct {
+	GoalID      uint             `json:"goal_id"`
+	Target      models.Money     `json:"target"` // Inflated target
+	Currency    string           `json:"currency"`
+	Deadline    time.Time        `json:"deadline"`
+	Months      int              `json:"months"`
+	Paths       int              `json:"paths"`
+	Seed        int64            `json:"seed"`
+	Probability float64          `json:"probability"` // Chance of reaching the target by the deadline, %
+	Bands       []SimulationBand `json:"bands"`
+
+	MissingFXRates []string `json:"missing_fx_rates,omitempty"` // Currency pairs of investments simulated as cash for lack of a rate
+}
+
+// SimulateGoal runs paths of random monthly returns for the goal's share of
+// each investment, using the expected return and volatility of its asset
+// class, and reports how likely the goal is to reach its inflated target by
+// the deadline. The goal's own monthly contribution follows the default
+// return and volatility. Results are repeatable for a given seed.
+func SimulateGoal(db *gorm.DB, goal models.Goal, assumptions ReturnAssumptions, paths int, seed int64, now time.Time) (GoalSimulation, error) {
+	simulation := GoalSimulation{
+		GoalID:   goal.ID,
+		Target:   goal.InflatedTarget(),
+		Currency: models.NormalizeCurrency(goal.Currency),
+		Deadline: goal.Deadline,
+		Months:   goal.MonthsRemaining(now),
+		Paths:    paths,
+		Seed:     seed,
+		Bands:    []SimulationBand{},
+	}
+	if goal.Deadline.IsZero() {
+		return simulation, ErrNoDeadline
+	}
+
+	holdings, missing, err := loadGoalHoldings(db, []models.Goal{goal}, now)
+	if err != nil {
+		return simulation, err
+	}
+	simulation.MissingFXRates = missing[goal.ID]
+
+	var assets []finance.SimulatedAsset
+	for _, h := range holdings[goal.ID] {
+		assets = append(assets, finance.SimulatedAsset{
+			Value:      h.Value.Float64(),
+			Monthly:    h.Monthly.Float64(),
+			Return:     assumptions.ExpectedReturn(h.Investment),
+			Volatility: assumptions.VolatilityOf(h.Investment),
+			Group:      h.Investment.AssetClass(),
+		})
+	}
+	if goal.MonthlyContribution > 0 {
+		assets = append(assets, finance.SimulatedAsset{
+			Monthly:    goal.MonthlyContribution.Float64(),
+			Return:     assumptions.Default,
+			Volatility: assumptions.DefaultVolatility,
+		})
+	}
+
+	// Savings not backed by investments, and investments without an exchange
+	// rate, are held as they are
+	var invested models.Money
+	for _, h := range holdings[goal.ID] {
+		invested += h.Value
+	}
+	if cash := goal.CurrentAmount - invested; cash > 0 {
+		assets = append(assets, finance.SimulatedAsset{Value: cash.Float64(), Group: models.AssetClassCash})
+	}
+
+	result := finance.MonteCarlo(assets, simulation.Target.Float64(), simulation.Months, paths, seed)
+	simulation.Probability = models.Round2(result.Probability)
+	start := models.SnapshotDate(now)
+	for _, band := range result.Bands {
+		simulation.Bands = append(simulation.Bands, SimulationBand{
+			Date: start.AddDate(0, band.Month, 0),
+			P10:  models.NewMoney(band.P10),
+			P25:  models.NewMoney(band.P25),
+			P50:  models.NewMoney(band.P50),
+			P75:  models.NewMoney(band.P75),
+			P90:  models.NewMoney(band.P90),
+		})
+	}
+	return simulation, nil
+}
+
+//
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/pricing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SIPPostSummary reports the outcome of posting due SIP installments
+type SIPPostSummary struct {
+	SIPsChecked        int `json:"sips_checked"`
+	InstallmentsPosted int `json:"installments_posted"`
+	GoalsRefreshed     int `json:"goals_refreshed"`
+}
+
+// PostDueSIPInstallments posts every active SIP installment due on or before
+// asOf. Units are bought at the provider's price for the due date when one is
+// known. Each SIP's installments are posted in one database transaction.
+func PostDueSIPInstallments(db *gorm.DB, provider pricing.PriceProvider, asOf time.Time) (SIPPostSummary, error) {
+	var summary SIPPostSummary
+
+	var sips []models.SIP
+	if err := db.Where("active = ? AND start_date <= ?", true, asOf).Find(&sips).Error; err != nil {
+		return summary, err
+	}
+
+	rules := NewStatusRules(db)
+	var posted []uint
+	for i := range sips {
+		sip := &sips[i]
+		summary.SIPsChecked++
+
+		dates := sip.DueDates(asOf)
+		if len(dates) == 0 {
+			continue
+		}
+
+		var inv models.Investment
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.First(&inv, sip.InvestmentID).Error; err != nil {
+				return err
+			}
+			for _, date := range dates {
+				if err := postInstallment(tx, rules, &inv, sip.Amount, date, provider); err != nil {
+					return err
+				}
+			}
+
+			last := dates[len(dates)-1]
+			sip.LastPostedOn = &last
+			return tx.Model(sip).Update("last_posted_on", last).Error
+		})
+		if err != nil {
+			return summary, err
+		}
+
+		summary.InstallmentsPosted += len(dates)
+		posted = append(posted, inv.ID)
+	}
+
+	goals, err := RefreshInvestmentGoals(db, posted...)
+	if err != nil {
+		return summary, err
+	}
+	summary.GoalsRefreshed = goals
+	return summary, nil
+}
+
+// postInstallment invests an amount on a date. Holdings with a transaction
+// ledger get a Buy transaction; others have their totals increased.
+func postInstallment(tx *gorm.DB, rules *StatusRules, inv *models.Investment, amount models.Money, date time.Time, provider pricing.PriceProvider) error {
+	units, price := 0.0, 0.0
+	if provider != nil && inv.Symbol != "" {
+		if p, err := provider.Price(inv.Symbol, date); err == nil && p > 0 {
+			price = p
+			units = amount.Float64() / p
+		}
+	}
+
+	var transactions []models.InvestmentTransaction
+	if err := tx.Where("investment_id = ?", inv.ID).Find(&transactions).Error; err != nil {
+		return err
+	}
+
+	if len(transactions) > 0 {
+		buy := models.InvestmentTransaction{
+			UserID:       inv.UserID,
+			InvestmentID: inv.ID,
+			Type:         models.TransactionBuy,
+			Units:        units,
+			Price:        price,
+			Amount:       amount,
+			Date:         date,
+			Notes:        "SIP installment",
+		}
+		if err := tx.Create(&buy).Error; err != nil {
+			return err
+		}
+		if units == 0 {
+			// Without a price the installment is valued at cost
+			inv.CurrentValue += amount
+		}
+		transactions = append(transactions, buy)
+		inv.ApplyLedger(transactions)
+	} else {
+		inv.AddPurchase(amount, units, price)
+	}
+	// The new transaction is not visible outside tx yet
+	if err := rules.ApplyWithLedger(inv, transactions); err != nil {
+		return err
+	}
+
+	return tx.Save(inv).Error
+}
+
+//
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"errors"
+	"investment-tracker-backend/models"
+	"investment-tracker-backend/pricing"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BuildSnapshot computes a user's portfolio snapshot for a day. Today's
+// snapshot uses the stored values; past days are rebuilt from the transaction
+// ledger and the price provider (which may be nil), falling back to cost when
+// no historical price is known. Amounts without an exchange rate are left out.
+func BuildSnapshot(db *gorm.DB, userID uint, date time.Time, provider pricing.PriceProvider) (models.PortfolioSnapshot, error) {
+	day := models.SnapshotDate(date)
+	endOfDay := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	live := !day.Before(models.SnapshotDate(time.Now()))
+
+	snapshot := models.PortfolioSnapshot{
+		UserID:        userID,
+		Date:          day,
+		TypeBreakdown: models.AmountBreakdown{},
+		GoalProgress:  models.GoalSnapshots{},
+	}
+
+	// Totals are kept in the user's base currency at the day's rates
+	var user models.User
+	if err := db.Select("id", "base_currency").First(&user, userID).Error; err != nil {
+		return snapshot, err
+	}
+	snapshot.Currency = models.NormalizeCurrency(user.BaseCurrency)
+	fx := NewFXConverter(db)
+
+	var investments []models.Investment
+	if err := db.Where("user_id = ?", userID).Find(&investments).Error; err != nil {
+		return snapshot, err
+	}
+
+	ledgers := make(map[uint][]models.InvestmentTransaction)
+	if !live {
+		var transactions []models.InvestmentTransaction
+		if err := db.Where("user_id = ? AND date <= ?", userID, endOfDay).Find(&transactions).Error; err != nil {
+			return snapshot, err
+		}
+		for _, t := range transactions {
+			ledgers[t.InvestmentID] = append(ledgers[t.InvestmentID], t)
+		}
+	}
+
+	var goals []models.Goal
+	if err := db.Where("user_id = ? AND created_at <= ?", userID, endOfDay).Find(&goals).Error; err != nil {
+		return snapshot, err
+	}
+	goalCurrencies := make(map[uint]string)
+	for _, goal := range goals {
+		goalCurrencies[goal.ID] = goal.Currency
+	}
+
+	var allocations []models.InvestmentGoalAllocation
+	if err := db.Where("user_id = ?", userID).Find(&allocations).Error; err != nil {
+		return snapshot, err
+	}
+	byInvestment := make(map[uint][]models.InvestmentGoalAllocation)
+	for _, a := range allocations {
+		byInvestment[a.InvestmentID] = append(byInvestment[a.InvestmentID], a)
+	}
+
+	goalValues := make(map[uint]models.Money)
+	for _, inv := range investments {
+		invested, value := inv.Invested, inv.CurrentValue
+		if !live {
+			var held bool
+			invested, value, held = valueAsOf(inv, ledgers[inv.ID], endOfDay, provider)
+			if !held {
+				continue
+			}
+		}
+
+		rate, err := fx.Rate(inv.Currency, snapshot.Currency, endOfDay)
+		if errors.Is(err, ErrNoFXRate) {
+			rate = 0
+		} else if err != nil {
+			return snapshot, err
+		}
+		snapshot.TotalInvested += invested.Mul(rate)
+		snapshot.CurrentValue += value.Mul(rate)
+		snapshot.TypeBreakdown[inv.Type] += value.Mul(rate)
+		for _, a := range byInvestment[inv.ID] {
+			goalValue, err := fx.Convert(a.Share(value), inv.Currency, goalCurrencies[a.GoalID], endOfDay)
+			if errors.Is(err, ErrNoFXRate) {
+				continue
+			}
+			if err != nil {
+				return snapshot, err
+			}
+			goalValues[a.GoalID] += goalValue
+		}
+	}
+
+	// Manual contributions count on top of the investments, as they do for
+	// current amounts
+	if !live {
+		for _, goal := range goals {
+			total, err := GoalContributionsTotal(db, fx, goal, endOfDay)
+			if err != nil {
+				return snapshot, err
+			}
+			goalValues[goal.ID] += total
+		}
+	}
+
+	for _, goal := range goals {
+		if !live {
+			goal.CurrentAmount = goalValues[goal.ID]
+			if goal.CurrentAmount < 0 {
+				goal.CurrentAmount = 0
+			}
+		}
+		snapshot.GoalProgress = append(snapshot.GoalProgress, models.GoalSnapshot{
+			GoalID:        goal.ID,
+			Name:          goal.Name,
+			TargetAmount:  goal.TargetAmount,
+			CurrentAmount: goal.CurrentAmount,
+			Progress:      goal.CalculateProgress(),
+		})
+	}
+
+	if missing := fx.Missing(); len(missing) > 0 {
+		log.Printf("Snapshot for user %d on %s: left out amounts without exchange rates for %s", userID, day.Format("2006-01-02"), strings.Join(missing, ", "))
+	}
+	return snapshot, nil
+}
+
+// valueAsOf returns what an investment had cost and was worth at a point in
+// time, and whether it was held at all by then
+func valueAsOf(inv models.Investment, ledger []models.InvestmentTransaction, at time.Time, provider pricing.PriceProvider) (invested models.Money, value models.Money, held bool) {
+	var units float64
+	if len(ledger) == 0 {
+		if inv.PurchaseDate.After(at) {
+			return 0, 0, false
+		}
+		invested, value, units = inv.Invested, inv.Invested, inv.Units
+	} else {
+		var past []models.InvestmentTransaction
+		for _, t := range ledger {
+			if !t.Date.After(at) {
+				past = append(past, t)
+			}
+		}
+		if len(past) == 0 {
+			return 0, 0, false
+		}
+
+		// Replay the ledger up to the date; valued at the last trade price
+		replay := models.Investment{}
+		replay.ApplyLedger(past)
+		invested, value, units = replay.Invested, replay.CurrentValue, replay.Units
+		if value == 0 && units == 0 {
+			value = invested
+		}
+	}
+
+	if provider != nil && inv.Symbol != "" && units > 0 {
+		if price, err := provider.Price(inv.Symbol, at); err == nil {
+			value = models.ValueAtPrice(units, price)
+		}
+	}
+	return invested, value, true
+}
+
+// SaveSnapshot stores a snapshot, replacing any existing one for the same user and day
+func SaveSnapshot(db *gorm.DB, snapshot *models.PortfolioSnapshot) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "currency", "total_invested", "current_value", "type_breakdown", "goal_progress"}),
+	}).Create(snapshot).Error
+}
+
+// SnapshotAllUsers records the snapshot for a day for every user
+func SnapshotAllUsers(db *gorm.DB, date time.Time, provider pricing.PriceProvider) (int, error) {
+	var userIDs []uint
+	if err := db.Model(&models.User{}).Pluck("id", &userIDs).Error; err != nil {
+		return 0, err
+	}
+
+	for _, userID := range userIDs {
+		snapshot, err := BuildSnapshot(db, userID, date, provider)
+		if err != nil {
+			return 0, err
+		}
+		if err := SaveSnapshot(db, &snapshot); err != nil {
+			return 0, err
+		}
+	}
+	return len(userIDs), nil
+}
+
+// BackfillSnapshots rebuilds a user's snapshots for every day in [from, to]
+func BackfillSnapshots(db *gorm.DB, userID uint, from, to time.Time, provider pricing.PriceProvider) (int, error) {
+	count := 0
+	for day := models.SnapshotDate(from); !day.After(models.SnapshotDate(to)); day = day.AddDate(0, 0, 1) {
+		snapshot, err := BuildSnapshot(db, userID, day, provider)
+		if err != nil {
+			return count, err
+		}
+		if err := SaveSnapshot(db, &snapshot); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+//
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"investment-tracker-backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StatusRules applies each user's custom status rules, falling back to the
+// default thresholds, and caches the rules while many investments are updated
+type StatusRules struct {
+	db     *gorm.DB
+	byUser map[uint][]models.StatusRule
+}
+
+// NewStatusRules creates a status rule cache reading from db
+func NewStatusRules(db *gorm.DB) *StatusRules {
+	return &StatusRules{db: db, byUser: make(map[uint][]models.StatusRule)}
+}
+
+// Apply sets the investment's status from its owner's rules. When a rule tests
+// the annualized return, the XIRR is first calculated from the investment's
+// transactions.
+func (s *StatusRules) Apply(inv *models.Investment) error {
+	rules, err := s.load(inv.UserID)
+	if err != nil {
+		return err
+	}
+
+	if inv.ID != 0 && usesAnnualizedReturn(rules, inv.Type) {
+		var transactions []models.InvestmentTransaction
+		if err := s.db.Where("investment_id = ?", inv.ID).Find(&transactions).Error; err != nil {
+			return err
+		}
+		inv.CalculateAnnualizedReturns(transactions, time.Now())
+	}
+	applyRules(inv, rules)
+	return nil
+}
+
+// ApplyWithLedger is Apply for an investment whose transactions are already
+// loaded, such as one being updated inside a database transaction
+func (s *StatusRules) ApplyWithLedger(inv *models.Investment, transactions []models.InvestmentTransaction) error {
+	rules, err := s.load(inv.UserID)
+	if err != nil {
+		return err
+	}
+
+	if usesAnnualizedReturn(rules, inv.Type) {
+		inv.CalculateAnnualizedReturns(transactions, time.Now())
+	}
+	applyRules(inv, rules)
+	return nil
+}
+
+// load returns a user's rules, reading them on first use
+func (s *StatusRules) load(userID uint) ([]models.StatusRule, error) {
+	rules, ok := s.byUser[userID]
+	if !ok {
+		if err := s.db.Where("user_id = ?", userID).Order("priority ASC, id ASC").Find(&rules).Error; err != nil {
+			return nil, err
+		}
+		s.byUser[userID] = rules
+	}
+	return rules, nil
+}
+
+// applyRules sets the status from the rules, or the default thresholds when none match
+func applyRules(inv *models.Investment, rules []models.StatusRule) {
+	if !inv.ApplyStatusRules(rules) {
+		inv.UpdateStatus()
+	}
+}
+
+// usesAnnualizedReturn reports whether any rule for the investment type tests the annualized return
+func usesAnnualizedReturn(rules []models.StatusRule, investmentType string) bool {
+	for _, rule := range rules {
+		if rule.Metric != models.MetricAnnualizedReturn {
+			continue
+		}
+		if rule.InvestmentType == "" || strings.EqualFold(rule.InvestmentType, investmentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateInvestmentStatus sets a single investment's status from its owner's rules
+func UpdateInvestmentStatus(db *gorm.DB, inv *models.Investment) error {
+	return NewStatusRules(db).Apply(inv)
+}
+
+// ReapplyStatusRules re-evaluates the status of every investment a user holds,
+// returning the number whose status changed
+func ReapplyStatusRules(db *gorm.DB, userID uint) (int, error) {
+	var investments []models.Investment
+	if err := db.Where("user_id = ?", userID).Find(&investments).Error; err != nil {
+		return 0, err
+	}
+
+	rules := NewStatusRules(db)
+	changed := 0
+	for i := range investments {
+		inv := &investments[i]
+		previous := inv.Status
+		if err := rules.Apply(inv); err != nil {
+			return changed, err
+		}
+		if inv.Status == previous {
+			continue
+		}
+		if err := db.Model(inv).Updates(map[string]interface{}{
+			"status":     inv.Status,
+			"peak_value": inv.PeakValue,
+		}).Error; err != nil {
+			return changed, err
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+//